@@ -0,0 +1,57 @@
+// Package buildinfo contains service build information set via ldflags:
+//
+//	go build -ldflags "\
+//	  -X github.com/tarusov/rig/buildinfo.Version=v1.2.3 \
+//	  -X github.com/tarusov/rig/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/tarusov/rig/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package buildinfo
+
+import (
+	"runtime"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Build variables, set via ldflags.
+var (
+	Version   string
+	Commit    string
+	BuildTime string
+)
+
+// Info is build information.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get return current build information.
+func Get() Info {
+	return Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+}
+
+// NewCollector create app_info gauge collector. Gauge value is always 1,
+// build information is set as labels.
+func NewCollector() prometheus.Collector {
+
+	var info = Get()
+	return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "app_info",
+		Help: "Application build information.",
+		ConstLabels: prometheus.Labels{
+			"version":    info.Version,
+			"commit":     info.Commit,
+			"build_time": info.BuildTime,
+			"go_version": info.GoVersion,
+		},
+	}, func() float64 {
+		return 1
+	})
+}
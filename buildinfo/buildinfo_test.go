@@ -0,0 +1,32 @@
+package buildinfo_test
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/buildinfo"
+)
+
+func TestCollector(t *testing.T) {
+
+	buildinfo.Version = "v1.2.3"
+	defer func() { buildinfo.Version = "" }()
+
+	var registry = prometheus.NewRegistry()
+	err := registry.Register(buildinfo.NewCollector())
+	require.ErrorIsf(t, err, nil, "TestCollector: unexpected register error: %v", err)
+
+	families, err := registry.Gather()
+	require.ErrorIsf(t, err, nil, "TestCollector: unexpected gather error: %v", err)
+	require.Lenf(t, families, 1, "TestCollector: unexpected metrics count")
+
+	var labels = make(map[string]string)
+	for _, lp := range families[0].GetMetric()[0].GetLabel() {
+		labels[lp.GetName()] = lp.GetValue()
+	}
+
+	require.Equalf(t, "app_info", families[0].GetName(), "TestCollector: unexpected metric name")
+	require.Equalf(t, "v1.2.3", labels["version"], "TestCollector: unexpected version label")
+	require.NotEmptyf(t, labels["go_version"], "TestCollector: go version label is empty")
+}
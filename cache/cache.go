@@ -0,0 +1,424 @@
+// Package cache contains two-tier cache: local LRU and redis. Local tiers of
+// instances are invalidated by broadcast over message queue.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/tarusov/rig/logger"
+	"github.com/tarusov/rig/metrics"
+	"github.com/tarusov/rig/mq"
+	"golang.org/x/sync/singleflight"
+)
+
+type (
+	// Cache struct.
+	Cache struct {
+		name        string
+		prefix      string
+		ttl         time.Duration
+		negativeTTL time.Duration
+		codec       Codec
+		local       *lru
+		localTTL    time.Duration
+		loadTimeout time.Duration
+		client      redis.UniversalClient
+		group       singleflight.Group
+
+		mq          mq.Client
+		queue       string
+		id          string
+		unsubscribe mq.UnsubscribeFunc
+
+		hits   metrics.Count
+		misses metrics.Count
+	}
+
+	// LoadFunc load value on cache miss. It should return ErrNotFound if value
+	// does not exist, such result is cached for negative ttl.
+	LoadFunc func(ctx context.Context) (interface{}, error)
+
+	// detachedContext keeps values of parent context, but not its cancellation.
+	detachedContext struct {
+		context.Context
+	}
+
+	// invalidation is local tiers invalidation message.
+	invalidation struct {
+		ID   string   `json:"id"`
+		Keys []string `json:"keys"`
+	}
+)
+
+// Entry flags, first byte of stored data.
+const (
+	flagValue    byte = 0
+	flagNotFound byte = 1
+)
+
+// Cache tiers.
+const (
+	tierLocal = "local"
+	tierRedis = "redis"
+)
+
+// Defaults.
+const (
+	defaultName        = "default"
+	defaultPrefix      = "cache:"
+	defaultTTL         = time.Hour
+	defaultLocalTTL    = time.Minute
+	defaultLoadTimeout = 30 * time.Second
+)
+
+// Aux error types.
+var (
+	ErrNotFound = errors.New("cache entry not found") // No entry or negative entry is cached.
+)
+
+// New creates new cache instance. At least one of local and redis tiers
+// should be set. If invalidation is set, ctx is used for its handler.
+func New(ctx context.Context, opts ...cacheOption) (*Cache, error) {
+
+	var co = &cacheOptions{
+		name:        defaultName,
+		prefix:      defaultPrefix,
+		ttl:         defaultTTL,
+		localTTL:    defaultLocalTTL,
+		loadTimeout: defaultLoadTimeout,
+		codec:       JSONCodec,
+	}
+
+	for _, opt := range opts {
+		opt(co)
+	}
+
+	if co.localSize <= 0 && co.client == nil {
+		return nil, errors.New("cache has no tiers")
+	}
+	if co.ttl <= 0 {
+		return nil, fmt.Errorf("invalid cache ttl: %v", co.ttl)
+	}
+	if co.loadTimeout <= 0 {
+		return nil, fmt.Errorf("invalid cache load timeout: %v", co.loadTimeout)
+	}
+
+	var c = &Cache{
+		name:        co.name,
+		prefix:      co.prefix,
+		ttl:         co.ttl,
+		negativeTTL: co.negativeTTL,
+		codec:       co.codec,
+		localTTL:    co.localTTL,
+		loadTimeout: co.loadTimeout,
+		client:      co.client,
+		mq:          co.mq,
+		queue:       co.queue,
+		id:          uuid.NewString(),
+	}
+
+	if co.localSize > 0 {
+		c.local = newLRU(co.localSize)
+	}
+
+	if co.registry != nil {
+		var err error
+
+		c.hits, err = metrics.NewCount(co.registry,
+			"cache_hits_total",
+			"Total number of cache hits.",
+			"cache", "tier",
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		c.misses, err = metrics.NewCount(co.registry,
+			"cache_misses_total",
+			"Total number of cache misses.",
+			"cache",
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.mq != nil && c.local != nil {
+		sub, ok := c.mq.(mq.Subscriber)
+		if !ok {
+			return nil, errors.New("invalidation client does not implement mq.Subscriber")
+		}
+
+		var err error
+		c.unsubscribe, err = sub.Subscribe(ctx, c.queue, c.invalidate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to subscribe invalidation: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+// Get decode cached value of key into dst. Return ErrNotFound on miss.
+func (c *Cache) Get(ctx context.Context, key string, dst interface{}) error {
+
+	data, err := c.get(ctx, c.prefix+key)
+	if err != nil {
+		return err
+	}
+
+	return c.decode(data, dst)
+}
+
+// Set cache value of key for ttl, default ttl is used if ttl is 0.
+func (c *Cache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache value: %w", err)
+	}
+
+	if ttl <= 0 {
+		ttl = c.ttl
+	}
+
+	return c.set(ctx, c.prefix+key, append([]byte{flagValue}, data...), ttl)
+}
+
+// Delete remove keys from all tiers.
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+
+	var fullKeys = make([]string, 0, len(keys))
+	for _, key := range keys {
+		fullKeys = append(fullKeys, c.prefix+key)
+	}
+
+	if c.local != nil {
+		for _, key := range fullKeys {
+			c.local.delete(key)
+		}
+	}
+
+	if c.client != nil && len(fullKeys) != 0 {
+		if err := c.client.Del(ctx, fullKeys...).Err(); err != nil {
+			return fmt.Errorf("failed to delete cache entries: %w", err)
+		}
+	}
+
+	c.broadcast(ctx, fullKeys...)
+
+	return nil
+}
+
+// GetOrLoad decode cached value of key into dst. On miss value is loaded by
+// fn and cached for ttl (default ttl if 0). Concurrent misses of key wait
+// for single load, each caller waits until its own ctx is done. Load is not
+// cancelled with caller ctx, it has own timeout. If cache fails, value is
+// loaded without caching.
+func (c *Cache) GetOrLoad(ctx context.Context, key string, dst interface{}, ttl time.Duration, fn LoadFunc) error {
+
+	var fullKey = c.prefix + key
+
+	data, err := c.get(ctx, fullKey)
+	if err == nil {
+		return c.decode(data, dst)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		logger.FromContext(ctx).WithField("key", key).WithErr(err).Warn("failed to get cache entry")
+	}
+
+	if ttl <= 0 {
+		ttl = c.ttl
+	}
+
+	var ch = c.group.DoChan(fullKey, func() (interface{}, error) {
+		lCtx, cancel := context.WithTimeout(detachedContext{ctx}, c.loadTimeout)
+		defer cancel()
+		return c.load(lCtx, fullKey, ttl, fn)
+	})
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return res.Err
+		}
+		return c.decode(res.Val.([]byte), dst)
+	}
+}
+
+// Close stop invalidation handling.
+func (c *Cache) Close() error {
+	if c.unsubscribe != nil {
+		return c.unsubscribe()
+	}
+	return nil
+}
+
+// Deadline implements context.Context Deadline method.
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Done implements context.Context Done method.
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+// Err implements context.Context Err method.
+func (detachedContext) Err() error {
+	return nil
+}
+
+// load call fn and cache its result.
+func (c *Cache) load(ctx context.Context, key string, ttl time.Duration, fn LoadFunc) ([]byte, error) {
+
+	var data []byte
+
+	value, err := fn(ctx)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		data = []byte{flagNotFound}
+		ttl = c.negativeTTL
+	case err != nil:
+		return nil, err
+	default:
+		encoded, err := c.codec.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode cache value: %w", err)
+		}
+		data = append([]byte{flagValue}, encoded...)
+	}
+
+	if ttl > 0 {
+		if err = c.set(ctx, key, data, ttl); err != nil {
+			logger.FromContext(ctx).WithField("key", key).WithErr(err).Warn("failed to set cache entry")
+		}
+	}
+
+	return data, nil
+}
+
+// get return entry data from local or redis tier.
+func (c *Cache) get(ctx context.Context, key string) ([]byte, error) {
+
+	if c.local != nil {
+		if data, ok := c.local.get(key); ok {
+			c.hit(tierLocal)
+			return data, nil
+		}
+	}
+
+	if c.client != nil {
+		data, err := c.client.Get(ctx, key).Bytes()
+		if err != nil && err != redis.Nil {
+			return nil, fmt.Errorf("failed to get cache entry: %w", err)
+		}
+
+		if err == nil {
+			c.hit(tierRedis)
+
+			if c.local != nil {
+				if ttl, err := c.client.PTTL(ctx, key).Result(); err == nil && ttl > 0 {
+					c.local.set(key, data, c.localEntryTTL(ttl))
+				}
+			}
+
+			return data, nil
+		}
+	}
+
+	if c.misses != nil {
+		c.misses.WithLabelValues(c.name).Inc()
+	}
+
+	return nil, ErrNotFound
+}
+
+// set store entry data into all tiers and invalidate other instances.
+func (c *Cache) set(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+
+	if c.client != nil {
+		if err := c.client.Set(ctx, key, data, ttl).Err(); err != nil {
+			return fmt.Errorf("failed to set cache entry: %w", err)
+		}
+	}
+
+	if c.local != nil {
+		c.local.set(key, data, c.localEntryTTL(ttl))
+	}
+
+	c.broadcast(ctx, key)
+
+	return nil
+}
+
+// decode entry data into dst.
+func (c *Cache) decode(data []byte, dst interface{}) error {
+
+	if len(data) == 0 || data[0] == flagNotFound {
+		return ErrNotFound
+	}
+
+	if err := c.codec.Unmarshal(data[1:], dst); err != nil {
+		return fmt.Errorf("failed to decode cache value: %w", err)
+	}
+
+	return nil
+}
+
+// localEntryTTL return local tier ttl of entry.
+func (c *Cache) localEntryTTL(ttl time.Duration) time.Duration {
+	if ttl > c.localTTL {
+		return c.localTTL
+	}
+	return ttl
+}
+
+// hit count cache hit.
+func (c *Cache) hit(tier string) {
+	if c.hits != nil {
+		c.hits.WithLabelValues(c.name, tier).Inc()
+	}
+}
+
+// broadcast send invalidation of keys to other instances.
+func (c *Cache) broadcast(ctx context.Context, keys ...string) {
+
+	if c.mq == nil || len(keys) == 0 {
+		return
+	}
+
+	msg, err := json.Marshal(invalidation{ID: c.id, Keys: keys})
+	if err == nil {
+		err = c.mq.Publish(ctx, c.queue, msg)
+	}
+	if err != nil {
+		logger.FromContext(ctx).WithErr(err).Warn("failed to broadcast cache invalidation")
+	}
+}
+
+// invalidate evict local entries invalidated by other instance.
+func (c *Cache) invalidate(ctx context.Context, msg []byte) {
+
+	var inv invalidation
+	if err := json.Unmarshal(msg, &inv); err != nil {
+		logger.FromContext(ctx).WithErr(err).Warn("failed to decode cache invalidation")
+		return
+	}
+
+	if inv.ID == c.id {
+		return
+	}
+
+	for _, key := range inv.Keys {
+		c.local.delete(key)
+	}
+}
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/tarusov/rig/metrics"
+	"github.com/tarusov/rig/mq"
+)
+
+type (
+	// cacheOption is cache constructor optional modificator.
+	cacheOption func(*cacheOptions)
+
+	// cacheOptions is auxilary constructor struct.
+	cacheOptions struct {
+		name        string
+		prefix      string
+		ttl         time.Duration
+		negativeTTL time.Duration
+		codec       Codec
+		localSize   int
+		localTTL    time.Duration
+		loadTimeout time.Duration
+		client      redis.UniversalClient
+		mq          mq.Client
+		queue       string
+		registry    metrics.Registry
+	}
+)
+
+// WithName setup cache name, it is used as metrics label.
+func WithName(name string) cacheOption {
+	return func(co *cacheOptions) {
+		co.name = name
+	}
+}
+
+// WithPrefix setup keys prefix ("cache:" by default).
+func WithPrefix(prefix string) cacheOption {
+	return func(co *cacheOptions) {
+		co.prefix = prefix
+	}
+}
+
+// WithTTL setup default entries ttl.
+func WithTTL(ttl time.Duration) cacheOption {
+	return func(co *cacheOptions) {
+		co.ttl = ttl
+	}
+}
+
+// WithNegativeTTL setup ttl of not found results, they are not cached by default.
+func WithNegativeTTL(ttl time.Duration) cacheOption {
+	return func(co *cacheOptions) {
+		co.negativeTTL = ttl
+	}
+}
+
+// WithCodec setup values codec (json by default).
+func WithCodec(codec Codec) cacheOption {
+	return func(co *cacheOptions) {
+		co.codec = codec
+	}
+}
+
+// WithLocal setup local LRU tier of size entries, entries are stored locally
+// no longer than ttl.
+func WithLocal(size int, ttl time.Duration) cacheOption {
+	return func(co *cacheOptions) {
+		co.localSize = size
+		co.localTTL = ttl
+	}
+}
+
+// WithRedis setup redis tier.
+func WithRedis(client redis.UniversalClient) cacheOption {
+	return func(co *cacheOptions) {
+		co.client = client
+	}
+}
+
+// WithLoadTimeout setup timeout of GetOrLoad load, it is shared by callers and
+// is not cancelled with caller context.
+func WithLoadTimeout(timeout time.Duration) cacheOption {
+	return func(co *cacheOptions) {
+		co.loadTimeout = timeout
+	}
+}
+
+// WithInvalidation setup local tiers invalidation broadcast over queue. Client
+// must implement mq.Subscriber, if local tier is set.
+func WithInvalidation(client mq.Client, queue string) cacheOption {
+	return func(co *cacheOptions) {
+		co.mq = client
+		co.queue = queue
+	}
+}
+
+// WithMetrics setup registry for hit/miss metrics.
+func WithMetrics(registry metrics.Registry) cacheOption {
+	return func(co *cacheOptions) {
+		co.registry = registry
+	}
+}
@@ -0,0 +1,348 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/cache"
+	"github.com/tarusov/rig/internal/testcontainer"
+	"github.com/tarusov/rig/metrics"
+	"github.com/tarusov/rig/mq"
+)
+
+// redisURI for tests. External server may be set with REDIS_URI env.
+var redisURI string
+
+func TestMain(m *testing.M) {
+
+	uri, terminate, err := testcontainer.Redis(context.Background())
+	if err != nil {
+		log.Println("redis container not inited", err)
+	}
+	redisURI = uri
+
+	var code = m.Run()
+	terminate()
+	os.Exit(code)
+}
+
+// newRedisClient for tests, skip test if redis is not available.
+func newRedisClient(t *testing.T) *redis.Client {
+
+	if redisURI == "" {
+		t.Skip("redis is not available")
+	}
+
+	opts, err := redis.ParseURL(redisURI)
+	require.ErrorIsf(t, err, nil, "%s: unexpected parse url error: %v", t.Name(), err)
+
+	var client = redis.NewClient(opts)
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+// memoryMQ is in-process mq client for tests.
+type memoryMQ struct {
+	mu       sync.Mutex
+	handlers map[string][]mq.Handler
+}
+
+func (m *memoryMQ) Publish(ctx context.Context, queue string, msg []byte) error {
+	m.mu.Lock()
+	var handlers = append([]mq.Handler(nil), m.handlers[queue]...)
+	m.mu.Unlock()
+
+	for _, h := range handlers {
+		h(ctx, msg)
+	}
+	return nil
+}
+
+func (m *memoryMQ) Subscribe(ctx context.Context, queue string, handler mq.Handler) (mq.UnsubscribeFunc, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.handlers == nil {
+		m.handlers = make(map[string][]mq.Handler)
+	}
+	m.handlers[queue] = append(m.handlers[queue], handler)
+
+	return func() error { return nil }, nil
+}
+
+func (m *memoryMQ) Close() error {
+	return nil
+}
+
+// publisherMQ is mq client without subscription support.
+type publisherMQ struct{}
+
+func (publisherMQ) Publish(context.Context, string, []byte) error {
+	return nil
+}
+
+func (publisherMQ) Close() error {
+	return nil
+}
+
+type item struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestLocal(t *testing.T) {
+
+	var ctx = context.Background()
+
+	c, err := cache.New(ctx, cache.WithLocal(2, time.Minute))
+	require.ErrorIsf(t, err, nil, "TestLocal: unexpected new error: %v", err)
+
+	var dst item
+	err = c.Get(ctx, "a", &dst)
+	require.ErrorIsf(t, err, cache.ErrNotFound, "TestLocal: unexpected miss error: %v", err)
+
+	for i, key := range []string{"a", "b", "c"} {
+		err = c.Set(ctx, key, item{ID: i, Name: key}, 0)
+		require.ErrorIsf(t, err, nil, "TestLocal: unexpected set error: %v", err)
+	}
+
+	// Size is 2, least recently used entry is evicted.
+	err = c.Get(ctx, "a", &dst)
+	require.ErrorIsf(t, err, cache.ErrNotFound, "TestLocal: lru entry not evicted: %v", err)
+
+	err = c.Get(ctx, "c", &dst)
+	require.ErrorIsf(t, err, nil, "TestLocal: unexpected get error: %v", err)
+	require.Equalf(t, item{ID: 2, Name: "c"}, dst, "TestLocal: unexpected value")
+
+	err = c.Delete(ctx, "c")
+	require.ErrorIsf(t, err, nil, "TestLocal: unexpected delete error: %v", err)
+
+	err = c.Get(ctx, "c", &dst)
+	require.ErrorIsf(t, err, cache.ErrNotFound, "TestLocal: entry not deleted: %v", err)
+
+	err = c.Set(ctx, "ttl", item{ID: 1}, 50*time.Millisecond)
+	require.ErrorIsf(t, err, nil, "TestLocal: unexpected set error: %v", err)
+
+	time.Sleep(100 * time.Millisecond)
+	err = c.Get(ctx, "ttl", &dst)
+	require.ErrorIsf(t, err, cache.ErrNotFound, "TestLocal: entry not expired: %v", err)
+
+	_, err = cache.New(ctx)
+	require.Errorf(t, err, "TestLocal: cache without tiers created")
+}
+
+func TestGetOrLoad(t *testing.T) {
+
+	var (
+		ctx      = context.Background()
+		registry = metrics.New()
+		loads    int32
+	)
+
+	c, err := cache.New(ctx,
+		cache.WithLocal(100, time.Minute),
+		cache.WithNegativeTTL(time.Minute),
+		cache.WithMetrics(registry),
+	)
+	require.ErrorIsf(t, err, nil, "TestGetOrLoad: unexpected new error: %v", err)
+
+	var (
+		release = make(chan struct{})
+		load    = func(ctx context.Context) (interface{}, error) {
+			atomic.AddInt32(&loads, 1)
+			<-release
+			return item{ID: 1, Name: "loaded"}, nil
+		}
+		wg sync.WaitGroup
+	)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var dst item
+			err := c.GetOrLoad(ctx, "key", &dst, 0, load)
+			assert.ErrorIsf(t, err, nil, "TestGetOrLoad: unexpected load error: %v", err)
+			assert.Equalf(t, "loaded", dst.Name, "TestGetOrLoad: unexpected value")
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.Equalf(t, int32(1), atomic.LoadInt32(&loads), "TestGetOrLoad: stampede not prevented")
+
+	var dst item
+	err = c.GetOrLoad(ctx, "key", &dst, 0, load)
+	require.ErrorIsf(t, err, nil, "TestGetOrLoad: unexpected get error: %v", err)
+	require.Equalf(t, int32(1), atomic.LoadInt32(&loads), "TestGetOrLoad: cached value not used")
+
+	// Not found result is cached.
+	var notFound = func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&loads, 1)
+		return nil, cache.ErrNotFound
+	}
+
+	for i := 0; i < 2; i++ {
+		err = c.GetOrLoad(ctx, "missing", &dst, 0, notFound)
+		require.ErrorIsf(t, err, cache.ErrNotFound, "TestGetOrLoad: unexpected not found error: %v", err)
+	}
+	require.Equalf(t, int32(2), atomic.LoadInt32(&loads), "TestGetOrLoad: negative entry not cached")
+
+	// Loader errors are not cached.
+	var errLoad = errors.New("load failed")
+	err = c.GetOrLoad(ctx, "failed", &dst, 0, func(ctx context.Context) (interface{}, error) {
+		return nil, errLoad
+	})
+	require.ErrorIsf(t, err, errLoad, "TestGetOrLoad: unexpected load error: %v", err)
+
+	err = c.Get(ctx, "failed", &dst)
+	require.ErrorIsf(t, err, cache.ErrNotFound, "TestGetOrLoad: failed load cached: %v", err)
+
+	families, err := registry.Gather()
+	require.ErrorIsf(t, err, nil, "TestGetOrLoad: unexpected gather error: %v", err)
+
+	var names = make(map[string]bool)
+	for _, mf := range families {
+		names[mf.GetName()] = true
+	}
+	require.Truef(t, names["cache_hits_total"], "TestGetOrLoad: hits not recorded")
+	require.Truef(t, names["cache_misses_total"], "TestGetOrLoad: misses not recorded")
+}
+
+func TestGetOrLoadCancel(t *testing.T) {
+
+	var ctx = context.Background()
+
+	c, err := cache.New(ctx, cache.WithLocal(100, time.Minute))
+	require.ErrorIsf(t, err, nil, "TestGetOrLoadCancel: unexpected new error: %v", err)
+
+	var (
+		release = make(chan struct{})
+		load    = func(ctx context.Context) (interface{}, error) {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-release:
+				return item{ID: 1, Name: "loaded"}, nil
+			}
+		}
+		done = make(chan error, 1)
+	)
+
+	// First caller gives up, load continues for others.
+	cCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		var dst item
+		done <- c.GetOrLoad(cCtx, "key", &dst, 0, load)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	var second = make(chan error, 1)
+	go func() {
+		var dst item
+		err := c.GetOrLoad(ctx, "key", &dst, 0, load)
+		assert.Equalf(t, "loaded", dst.Name, "TestGetOrLoadCancel: unexpected value")
+		second <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	cancel()
+	require.ErrorIsf(t, <-done, context.Canceled, "TestGetOrLoadCancel: unexpected cancelled caller error")
+
+	close(release)
+	require.ErrorIsf(t, <-second, nil, "TestGetOrLoadCancel: unexpected load error")
+}
+
+func TestRedis(t *testing.T) {
+
+	var (
+		ctx    = context.Background()
+		client = newRedisClient(t)
+		prefix = uuid.NewString() + ":"
+	)
+
+	c1, err := cache.New(ctx, cache.WithRedis(client), cache.WithLocal(10, time.Minute), cache.WithPrefix(prefix))
+	require.ErrorIsf(t, err, nil, "TestRedis: unexpected new error: %v", err)
+
+	c2, err := cache.New(ctx, cache.WithRedis(client), cache.WithPrefix(prefix))
+	require.ErrorIsf(t, err, nil, "TestRedis: unexpected new error: %v", err)
+
+	err = c1.Set(ctx, "key", item{ID: 1, Name: "shared"}, time.Minute)
+	require.ErrorIsf(t, err, nil, "TestRedis: unexpected set error: %v", err)
+
+	var dst item
+	err = c2.Get(ctx, "key", &dst)
+	require.ErrorIsf(t, err, nil, "TestRedis: unexpected get error: %v", err)
+	require.Equalf(t, "shared", dst.Name, "TestRedis: unexpected value")
+
+	ttl, err := client.PTTL(ctx, prefix+"key").Result()
+	require.ErrorIsf(t, err, nil, "TestRedis: unexpected pttl error: %v", err)
+	require.Truef(t, ttl > 0 && ttl <= time.Minute, "TestRedis: unexpected ttl: %v", ttl)
+
+	err = c2.Delete(ctx, "key")
+	require.ErrorIsf(t, err, nil, "TestRedis: unexpected delete error: %v", err)
+
+	err = c2.Get(ctx, "key", &dst)
+	require.ErrorIsf(t, err, cache.ErrNotFound, "TestRedis: entry not deleted: %v", err)
+}
+
+func TestInvalidation(t *testing.T) {
+
+	var (
+		ctx    = context.Background()
+		client = newRedisClient(t)
+		prefix = uuid.NewString() + ":"
+		broker = &memoryMQ{}
+	)
+
+	var newCache = func() *cache.Cache {
+		c, err := cache.New(ctx,
+			cache.WithRedis(client),
+			cache.WithLocal(10, time.Minute),
+			cache.WithPrefix(prefix),
+			cache.WithInvalidation(broker, "cache.invalidate"),
+		)
+		require.ErrorIsf(t, err, nil, "TestInvalidation: unexpected new error: %v", err)
+		t.Cleanup(func() { c.Close() })
+		return c
+	}
+
+	_, err := cache.New(ctx, cache.WithLocal(10, time.Minute), cache.WithInvalidation(publisherMQ{}, "cache.invalidate"))
+	require.Errorf(t, err, "TestInvalidation: client without subscription accepted")
+
+	var c1, c2 = newCache(), newCache()
+
+	err = c1.Set(ctx, "key", item{ID: 1}, 0)
+	require.ErrorIsf(t, err, nil, "TestInvalidation: unexpected set error: %v", err)
+
+	// Fill local tier of second instance.
+	var dst item
+	err = c2.Get(ctx, "key", &dst)
+	require.ErrorIsf(t, err, nil, "TestInvalidation: unexpected get error: %v", err)
+
+	err = c1.Set(ctx, "key", item{ID: 2}, 0)
+	require.ErrorIsf(t, err, nil, "TestInvalidation: unexpected set error: %v", err)
+
+	err = c2.Get(ctx, "key", &dst)
+	require.ErrorIsf(t, err, nil, "TestInvalidation: unexpected get error: %v", err)
+	require.Equalf(t, 2, dst.ID, "TestInvalidation: stale local entry not evicted")
+
+	err = c1.Delete(ctx, "key")
+	require.ErrorIsf(t, err, nil, "TestInvalidation: unexpected delete error: %v", err)
+
+	err = c2.Get(ctx, "key", &dst)
+	require.ErrorIsf(t, err, cache.ErrNotFound, "TestInvalidation: deleted local entry not evicted: %v", err)
+}
@@ -0,0 +1,27 @@
+package cache
+
+import "encoding/json"
+
+type (
+	// Codec is cached values serializer.
+	Codec interface {
+		Marshal(v interface{}) ([]byte, error)
+		Unmarshal(data []byte, v interface{}) error
+	}
+
+	// jsonCodec is json values serializer.
+	jsonCodec struct{}
+)
+
+// JSONCodec is default codec.
+var JSONCodec Codec = jsonCodec{}
+
+// Marshal value into json.
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal json into value.
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
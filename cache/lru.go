@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type (
+	// lru is local least recently used entries cache.
+	lru struct {
+		mu      sync.Mutex
+		size    int
+		items   map[string]*list.Element
+		entries *list.List
+	}
+
+	// lruEntry is local cache entry.
+	lruEntry struct {
+		key     string
+		data    []byte
+		expires time.Time
+	}
+)
+
+// newLRU creates local cache of size entries.
+func newLRU(size int) *lru {
+	return &lru{
+		size:    size,
+		items:   make(map[string]*list.Element, size),
+		entries: list.New(),
+	}
+}
+
+// get return not expired entry data.
+func (c *lru) get(key string) ([]byte, bool) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	var e = el.Value.(*lruEntry)
+	if !time.Now().Before(e.expires) {
+		c.remove(el)
+		return nil, false
+	}
+
+	c.entries.MoveToFront(el)
+
+	return e.data, true
+}
+
+// set add or replace entry, least recently used entry is evicted if cache is full.
+func (c *lru) set(key string, data []byte, ttl time.Duration) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires = time.Now().Add(ttl)
+
+	if el, ok := c.items[key]; ok {
+		var e = el.Value.(*lruEntry)
+		e.data, e.expires = data, expires
+		c.entries.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.entries.PushFront(&lruEntry{
+		key:     key,
+		data:    data,
+		expires: expires,
+	})
+
+	if c.entries.Len() > c.size {
+		c.remove(c.entries.Back())
+	}
+}
+
+// delete remove entry.
+func (c *lru) delete(key string) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+}
+
+// remove entry element. Must be called under mutex.
+func (c *lru) remove(el *list.Element) {
+	c.entries.Remove(el)
+	delete(c.items, el.Value.(*lruEntry).key)
+}
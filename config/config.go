@@ -0,0 +1,277 @@
+// Package config contains struct loader from defaults, files, env and flags.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/tarusov/rig/validator"
+	"gopkg.in/yaml.v3"
+)
+
+// List of supported struct tags.
+const (
+	TagEnv     = "env"     // Env variable name.
+	TagFlag    = "flag"    // Command line flag name.
+	TagDefault = "default" // Default value.
+	TagUsage   = "usage"   // Flag usage description.
+	TagSecret  = "secret"  // Value is redacted when printing ("true").
+)
+
+// Load populate struct pointed by dst. Sources are applied in order:
+// default tags, file, env variables, command line flags. Result is
+// validated by validator "validate" tags.
+func Load(dst interface{}, opts ...loaderOption) error {
+
+	var lo = &loaderOptions{
+		env: true,
+	}
+
+	for _, opt := range opts {
+		opt(lo)
+	}
+
+	var rv = reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("config destination must be non-nil struct pointer")
+	}
+
+	var fields, err = collectFields(rv.Elem(), "", "")
+	if err != nil {
+		return err
+	}
+
+	for _, f := range fields {
+		if f.defValue == "" {
+			continue
+		}
+		if err := setValue(f.value, f.defValue); err != nil {
+			return fmt.Errorf("invalid default value for %s: %w", f.path, err)
+		}
+	}
+
+	if lo.file != "" {
+		var data = lo.fileData
+		if data == nil {
+			if data, err = os.ReadFile(lo.file); err != nil {
+				return fmt.Errorf("failed to read config file: %w", err)
+			}
+		}
+		if err := decodeFile(lo.file, data, dst); err != nil {
+			return err
+		}
+	}
+
+	if lo.env {
+		for _, f := range fields {
+			if f.env == "" {
+				continue
+			}
+			if v, ok := os.LookupEnv(lo.envPrefix + f.env); ok {
+				if err := setValue(f.value, v); err != nil {
+					return fmt.Errorf("invalid env %s value: %w", lo.envPrefix+f.env, err)
+				}
+			}
+		}
+	}
+
+	if lo.flagSet != nil {
+		if err := loadFlags(lo.flagSet, lo.args, fields); err != nil {
+			return err
+		}
+	}
+
+	var v = lo.validator
+	if v == nil {
+		if v, err = validator.New(); err != nil {
+			return fmt.Errorf("failed to create validator: %w", err)
+		}
+	}
+
+	fieldErrors, err := v.Struct(dst)
+	if len(fieldErrors) != 0 {
+		return fieldErrors
+	}
+
+	return err
+}
+
+// decodeFile decode yaml or json file content into dst.
+func decodeFile(path string, data []byte, dst interface{}) error {
+
+	var err error
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, dst)
+	case ".json":
+		err = unmarshalJSON(data, dst)
+	default:
+		return fmt.Errorf("unsupported config file format: %q", path)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to decode config file: %w", err)
+	}
+
+	return nil
+}
+
+// loadFlags register fields flags, parse args and apply explicitly set flags.
+// Already parsed flag set is not parsed again, only set flags are applied.
+func loadFlags(fs *flag.FlagSet, args []string, fields []field) error {
+
+	if !fs.Parsed() {
+		for _, f := range fields {
+			if f.flag != "" {
+				fs.String(f.flag, f.defValue, f.usage)
+			}
+		}
+
+		if err := fs.Parse(args); err != nil {
+			return fmt.Errorf("failed to parse flags: %w", err)
+		}
+	}
+
+	var visited = make(map[string]string)
+	fs.Visit(func(fl *flag.Flag) {
+		visited[fl.Name] = fl.Value.String()
+	})
+
+	for _, f := range fields {
+		if f.flag == "" {
+			continue
+		}
+		if v, ok := visited[f.flag]; ok {
+			if err := setValue(f.value, v); err != nil {
+				return fmt.Errorf("invalid flag -%s value: %w", f.flag, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// unmarshalJSON decode json into dst. Duration fields may be set by strings
+// like "5s", same as in yaml and env.
+func unmarshalJSON(data []byte, dst interface{}) error {
+
+	var dec = json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(parseDurations(v, reflect.TypeOf(dst)))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, dst)
+}
+
+// parseDurations replace duration strings of decoded json value v with
+// nanoseconds according to destination type t.
+func parseDurations(v interface{}, t reflect.Type) interface{} {
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == durationType {
+		if s, ok := v.(string); ok {
+			if d, err := time.ParseDuration(s); err == nil {
+				return int64(d)
+			}
+		}
+		return v
+	}
+
+	if reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		return v
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if m, ok := v.(map[string]interface{}); ok {
+			for k, fv := range m {
+				if ft, ok := jsonFieldType(t, k); ok {
+					m[k] = parseDurations(fv, ft)
+				}
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if a, ok := v.([]interface{}); ok {
+			for i := range a {
+				a[i] = parseDurations(a[i], t.Elem())
+			}
+		}
+	case reflect.Map:
+		if m, ok := v.(map[string]interface{}); ok {
+			for k := range m {
+				m[k] = parseDurations(m[k], t.Elem())
+			}
+		}
+	}
+
+	return v
+}
+
+// jsonFieldType return type of struct field decoded from json key. Fields
+// are matched like encoding/json does: by tag or name, case-insensitive.
+func jsonFieldType(t reflect.Type, key string) (reflect.Type, bool) {
+
+	var (
+		folded reflect.Type
+		found  bool
+	)
+
+	for i := 0; i < t.NumField(); i++ {
+		var sf = t.Field(i)
+
+		var name = strings.SplitN(sf.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			continue
+		}
+
+		if sf.Anonymous && name == "" {
+			var et = sf.Type
+			if et.Kind() == reflect.Ptr {
+				et = et.Elem()
+			}
+			if et.Kind() == reflect.Struct {
+				if ft, ok := jsonFieldType(et, key); ok && !found {
+					folded, found = ft, true
+				}
+				continue
+			}
+		}
+
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+
+		if name == key {
+			return sf.Type, true
+		}
+		if !found && strings.EqualFold(name, key) {
+			folded, found = sf.Type, true
+		}
+	}
+
+	return folded, found
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"flag"
+	"time"
+
+	"github.com/tarusov/rig/validator"
+)
+
+type (
+	// loaderOption is loader optional modificator.
+	loaderOption func(*loaderOptions)
+
+	// loaderOptions is auxilary loader struct.
+	loaderOptions struct {
+		file      string
+		env       bool
+		envPrefix string
+		flagSet   *flag.FlagSet
+		args      []string
+		validator *validator.Validator
+
+		pollInterval time.Duration
+		fileData     []byte // Already read file content, it is used by watcher.
+	}
+)
+
+// WithFile setup config file path. Format is defined by extension (.yaml, .yml, .json).
+func WithFile(path string) loaderOption {
+	return func(lo *loaderOptions) {
+		lo.file = path
+	}
+}
+
+// WithEnvPrefix setup env variables prefix (APP_ for APP_LOG_LEVEL, etc).
+func WithEnvPrefix(prefix string) loaderOption {
+	return func(lo *loaderOptions) {
+		lo.envPrefix = prefix
+	}
+}
+
+// WithoutEnv disable env variables source.
+func WithoutEnv() loaderOption {
+	return func(lo *loaderOptions) {
+		lo.env = false
+	}
+}
+
+// WithFlags setup command line flags source. Flags from "flag" tags are
+// registered in flag set and args are parsed (os.Args[1:] usually).
+func WithFlags(fs *flag.FlagSet, args []string) loaderOption {
+	return func(lo *loaderOptions) {
+		lo.flagSet = fs
+		lo.args = args
+	}
+}
+
+// WithValidator setup custom validator. Default validator is used otherwise.
+func WithValidator(v *validator.Validator) loaderOption {
+	return func(lo *loaderOptions) {
+		lo.validator = v
+	}
+}
+
+// WithPollInterval setup config file polling for Watcher instead of file system notifications.
+func WithPollInterval(interval time.Duration) loaderOption {
+	return func(lo *loaderOptions) {
+		lo.pollInterval = interval
+	}
+}
+
+// withFileData setup already read config file content, so file is not read again.
+func withFileData(data []byte) loaderOption {
+	return func(lo *loaderOptions) {
+		lo.fileData = data
+	}
+}
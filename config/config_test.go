@@ -0,0 +1,114 @@
+package config_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/config"
+	"github.com/tarusov/rig/logger"
+	"github.com/tarusov/rig/validator"
+)
+
+type testConfig struct {
+	LogLevel logger.Level  `yaml:"log_level" env:"LOG_LEVEL" flag:"log-level" default:"info" validate:"oneof=debug info warn error"`
+	Timeout  time.Duration `yaml:"timeout" env:"TIMEOUT" default:"5s"`
+	Servers  []string      `yaml:"servers" env:"SERVERS"`
+	Redis    struct {
+		Addr     string `yaml:"addr" env:"ADDR" default:"localhost:6379"`
+		Password string `yaml:"password" env:"PASSWORD" secret:"true"`
+	} `yaml:"redis" env:"REDIS"`
+}
+
+func TestLoad(t *testing.T) {
+
+	var path = filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte("log_level: warn\nservers: [a, b]\nredis:\n  password: file-secret\n"), 0600)
+	require.ErrorIsf(t, err, nil, "TestLoad: unexpected write error: %v", err)
+
+	t.Setenv("APP_TIMEOUT", "10s")
+	t.Setenv("APP_REDIS_ADDR", "redis:6379")
+
+	var cfg testConfig
+	err = config.Load(&cfg,
+		config.WithFile(path),
+		config.WithEnvPrefix("APP_"),
+		config.WithFlags(flag.NewFlagSet("test", flag.ContinueOnError), []string{"-log-level", "error"}),
+	)
+	require.ErrorIsf(t, err, nil, "TestLoad: unexpected load error: %v", err)
+
+	require.Equalf(t, logger.LevelError, cfg.LogLevel, "TestLoad: flag value not applied")
+	require.Equalf(t, 10*time.Second, cfg.Timeout, "TestLoad: env value not applied")
+	require.Equalf(t, []string{"a", "b"}, cfg.Servers, "TestLoad: file value not applied")
+	require.Equalf(t, "redis:6379", cfg.Redis.Addr, "TestLoad: nested env value not applied")
+	require.Equalf(t, "file-secret", cfg.Redis.Password, "TestLoad: nested file value not applied")
+
+	var s = config.String(cfg)
+	require.NotContainsf(t, s, "file-secret", "TestLoad: secret not redacted")
+	require.Containsf(t, s, config.RedactedValue, "TestLoad: secret placeholder not found")
+}
+
+func TestLoadValidation(t *testing.T) {
+
+	t.Setenv("LOG_LEVEL", "verbose")
+
+	var cfg testConfig
+	err := config.Load(&cfg)
+
+	fieldErrors, ok := err.(validator.FieldErrors)
+	require.Truef(t, ok, "TestLoadValidation: unexpected error type %T", err)
+	require.Containsf(t, fieldErrors, "LogLevel", "TestLoadValidation: field error not found")
+}
+
+func TestLoadJSONDuration(t *testing.T) {
+
+	type jsonConfig struct {
+		Timeout time.Duration   `json:"timeout"`
+		Retry   time.Duration   `json:"retry"`
+		Steps   []time.Duration `json:"steps"`
+		Nested  struct {
+			Interval *time.Duration `json:"interval"`
+		} `json:"nested"`
+	}
+
+	var path = filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(path, []byte(`{"timeout":"5s","retry":1000,"steps":["1s","2m"],"nested":{"interval":"150ms"}}`), 0600)
+	require.ErrorIsf(t, err, nil, "TestLoadJSONDuration: unexpected write error: %v", err)
+
+	var cfg jsonConfig
+	err = config.Load(&cfg, config.WithFile(path), config.WithoutEnv())
+	require.ErrorIsf(t, err, nil, "TestLoadJSONDuration: unexpected load error: %v", err)
+
+	require.Equalf(t, 5*time.Second, cfg.Timeout, "TestLoadJSONDuration: duration string not decoded")
+	require.Equalf(t, time.Duration(1000), cfg.Retry, "TestLoadJSONDuration: duration number not decoded")
+	require.Equalf(t, []time.Duration{time.Second, 2 * time.Minute}, cfg.Steps, "TestLoadJSONDuration: duration slice not decoded")
+	require.NotNilf(t, cfg.Nested.Interval, "TestLoadJSONDuration: nested duration not decoded")
+	require.Equalf(t, 150*time.Millisecond, *cfg.Nested.Interval, "TestLoadJSONDuration: nested duration not decoded")
+}
+
+func TestRedactedNested(t *testing.T) {
+
+	type credentials struct {
+		User     string `json:"user"`
+		Password string `json:"password" secret:"true"`
+	}
+
+	var cfg = struct {
+		Replicas []credentials          `json:"replicas"`
+		Backends map[string]credentials `json:"backends"`
+		Tags     []string               `json:"tags"`
+	}{
+		Replicas: []credentials{{User: "a", Password: "slice-secret"}},
+		Backends: map[string]credentials{"main": {User: "b", Password: "map-secret"}},
+		Tags:     []string{"x"},
+	}
+
+	var s = config.String(cfg)
+	require.NotContainsf(t, s, "slice-secret", "TestRedactedNested: secret in slice not redacted")
+	require.NotContainsf(t, s, "map-secret", "TestRedactedNested: secret in map not redacted")
+	require.Containsf(t, s, `"user":"a"`, "TestRedactedNested: slice struct not rendered")
+	require.Containsf(t, s, `"tags":["x"]`, "TestRedactedNested: plain slice not rendered")
+}
@@ -0,0 +1,214 @@
+package config
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// field is loadable struct field.
+type field struct {
+	value    reflect.Value
+	path     string
+	env      string
+	flag     string
+	usage    string
+	defValue string
+}
+
+// Reflect types of values with special decoding.
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+)
+
+// collectFields walk struct recursively and collect settable fields.
+// Env tag of nested struct is used as prefix for its fields env names.
+func collectFields(rv reflect.Value, path, envPrefix string) ([]field, error) {
+
+	var (
+		rt     = rv.Type()
+		fields = make([]field, 0, rt.NumField())
+	)
+
+	for i := 0; i < rt.NumField(); i++ {
+		var (
+			sf = rt.Field(i)
+			fv = rv.Field(i)
+		)
+
+		if sf.PkgPath != "" {
+			continue // Unexported.
+		}
+
+		var fieldPath = sf.Name
+		if path != "" {
+			fieldPath = path + "." + sf.Name
+		}
+
+		if isNested(fv) {
+			var prefix = envPrefix
+			if env := sf.Tag.Get(TagEnv); env != "" {
+				prefix = envPrefix + env + "_"
+			}
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					fv.Set(reflect.New(fv.Type().Elem()))
+				}
+				fv = fv.Elem()
+			}
+
+			nested, err := collectFields(fv, fieldPath, prefix)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, nested...)
+			continue
+		}
+
+		var f = field{
+			value:    fv,
+			path:     fieldPath,
+			flag:     sf.Tag.Get(TagFlag),
+			usage:    sf.Tag.Get(TagUsage),
+			defValue: sf.Tag.Get(TagDefault),
+		}
+		if env := sf.Tag.Get(TagEnv); env != "" {
+			f.env = envPrefix + env
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields, nil
+}
+
+// isNested check if value is nested config struct (not a leaf value).
+func isNested(fv reflect.Value) bool {
+	return isNestedType(fv.Type())
+}
+
+// isNestedType check if type is nested config struct or pointer to it.
+func isNestedType(ft reflect.Type) bool {
+
+	if ft.Kind() == reflect.Ptr {
+		ft = ft.Elem()
+	}
+
+	if ft.Kind() != reflect.Struct || ft == reflect.TypeOf(time.Time{}) {
+		return false
+	}
+
+	return !reflect.PtrTo(ft).Implements(textUnmarshalerType)
+}
+
+// setValue parse string and set it into field value.
+func setValue(fv reflect.Value, s string) error {
+
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return setValue(fv.Elem(), s)
+	}
+
+	if fv.CanAddr() && fv.Addr().Type().Implements(textUnmarshalerType) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if fv.Type() == durationType {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return err
+			}
+			fv.SetInt(int64(d))
+			return nil
+		}
+		n, err := strconv.ParseInt(s, 0, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 0, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(n)
+
+	case reflect.Slice:
+		var (
+			parts = splitList(s)
+			slice = reflect.MakeSlice(fv.Type(), len(parts), len(parts))
+		)
+		for i, part := range parts {
+			if err := setValue(slice.Index(i), part); err != nil {
+				return err
+			}
+		}
+		fv.Set(slice)
+
+	case reflect.Map:
+		var m = reflect.MakeMap(fv.Type())
+		for _, part := range splitList(s) {
+			kv := strings.SplitN(part, "=", 2)
+			if len(kv) != 2 {
+				return fmt.Errorf("invalid map item %q, key=value expected", part)
+			}
+			var (
+				k = reflect.New(fv.Type().Key()).Elem()
+				v = reflect.New(fv.Type().Elem()).Elem()
+			)
+			if err := setValue(k, kv[0]); err != nil {
+				return err
+			}
+			if err := setValue(v, kv[1]); err != nil {
+				return err
+			}
+			m.SetMapIndex(k, v)
+		}
+		fv.Set(m)
+
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+
+	return nil
+}
+
+// splitList split comma separated list, empty string is empty list.
+func splitList(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	var parts = strings.Split(s, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
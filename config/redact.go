@@ -0,0 +1,132 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RedactedValue is placeholder for secret values.
+const RedactedValue = "[REDACTED]"
+
+// Redacted return config representation with secret fields replaced by placeholder.
+// Field names are taken from yaml or json tags, struct field names are used otherwise.
+func Redacted(cfg interface{}) map[string]interface{} {
+
+	var rv = reflect.ValueOf(cfg)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	return redactStruct(rv)
+}
+
+// String return json representation of config with redacted secrets.
+func String(cfg interface{}) string {
+	data, err := json.Marshal(Redacted(cfg))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// redactStruct convert struct into map with redacted secrets.
+func redactStruct(rv reflect.Value) map[string]interface{} {
+
+	var (
+		rt  = rv.Type()
+		out = make(map[string]interface{}, rt.NumField())
+	)
+
+	for i := 0; i < rt.NumField(); i++ {
+		var (
+			sf = rt.Field(i)
+			fv = rv.Field(i)
+		)
+
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		var name = fieldName(sf)
+		if name == "-" {
+			continue
+		}
+
+		if sf.Tag.Get(TagSecret) == "true" {
+			if !fv.IsZero() {
+				out[name] = RedactedValue
+			} else {
+				out[name] = ""
+			}
+			continue
+		}
+
+		out[name] = redactValue(fv)
+	}
+
+	return out
+}
+
+// redactValue return value with redacted secrets of nested structs,
+// including structs in slices and maps.
+func redactValue(fv reflect.Value) interface{} {
+
+	if isNested(fv) {
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				return nil
+			}
+			fv = fv.Elem()
+		}
+		return redactStruct(fv)
+	}
+
+	switch fv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if !isNestedType(fv.Type().Elem()) {
+			break
+		}
+		if fv.Kind() == reflect.Slice && fv.IsNil() {
+			return nil
+		}
+		var out = make([]interface{}, fv.Len())
+		for i := range out {
+			out[i] = redactValue(fv.Index(i))
+		}
+		return out
+
+	case reflect.Map:
+		if !isNestedType(fv.Type().Elem()) {
+			break
+		}
+		if fv.IsNil() {
+			return nil
+		}
+		var out = make(map[string]interface{}, fv.Len())
+		for iter := fv.MapRange(); iter.Next(); {
+			out[fmt.Sprint(iter.Key().Interface())] = redactValue(iter.Value())
+		}
+		return out
+	}
+
+	return fv.Interface()
+}
+
+// fieldName return field name from yaml/json tags or struct field name.
+func fieldName(sf reflect.StructField) string {
+	for _, tag := range []string{"yaml", "json"} {
+		if name := strings.SplitN(sf.Tag.Get(tag), ",", 2)[0]; name != "" {
+			return name
+		}
+	}
+	return sf.Name
+}
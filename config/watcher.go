@@ -0,0 +1,282 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/tarusov/rig/logger"
+)
+
+type (
+	// Watcher struct.
+	Watcher struct {
+		mu       sync.RWMutex
+		reloadMu sync.Mutex // Serializes reloads, so changes are notified in order.
+		current  interface{}
+		typ      reflect.Type
+		opts     []loaderOption
+		lo       *loaderOptions
+		hash     []byte
+		subs     []subscription
+	}
+
+	// subscription is change callback for config field.
+	subscription struct {
+		path []string
+		fn   reflect.Value
+	}
+)
+
+// debounceDelay is delay between file event and reload, editors produce several events on save.
+const debounceDelay = 100 * time.Millisecond
+
+// NewWatcher load config into cfg (struct pointer) and return watcher for config
+// file changes. Config file must be set with WithFile option.
+func NewWatcher(cfg interface{}, opts ...loaderOption) (*Watcher, error) {
+
+	var lo = &loaderOptions{}
+	for _, opt := range opts {
+		opt(lo)
+	}
+
+	if lo.file == "" {
+		return nil, errors.New("config file is not set")
+	}
+
+	hash, err := loadHashed(cfg, lo.file, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Watcher{
+		current: cfg,
+		typ:     reflect.TypeOf(cfg).Elem(),
+		opts:    opts,
+		lo:      lo,
+		hash:    hash,
+	}, nil
+}
+
+// Current return current config snapshot, it has same type as NewWatcher cfg.
+// Snapshot is replaced on reload and must not be modified.
+func (w *Watcher) Current() interface{} {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current
+}
+
+// Subscribe add change callback for config field path ("Log.Level"), empty
+// path means whole config. Callback must be func(old, new T), where T is
+// field type (or config pointer type for whole config).
+func (w *Watcher) Subscribe(path string, fn interface{}) error {
+
+	var (
+		names []string
+		ft    = reflect.PtrTo(w.typ)
+	)
+
+	if path != "" {
+		names = strings.Split(path, ".")
+		ft = w.typ
+		for _, name := range names {
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() != reflect.Struct {
+				return fmt.Errorf("config field %q not found", path)
+			}
+			sf, ok := ft.FieldByName(name)
+			if !ok {
+				return fmt.Errorf("config field %q not found", path)
+			}
+			ft = sf.Type
+		}
+	}
+
+	var fv = reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func ||
+		fv.Type().NumIn() != 2 || fv.Type().NumOut() != 0 ||
+		fv.Type().In(0) != ft || fv.Type().In(1) != ft {
+		return fmt.Errorf("config field %q callback must be func(old, new %s)", path, ft)
+	}
+
+	w.mu.Lock()
+	w.subs = append(w.subs, subscription{path: names, fn: fv})
+	w.mu.Unlock()
+
+	return nil
+}
+
+// Reload config from sources and notify subscribers about changed values.
+// If new config is invalid, current config is kept. Concurrent reloads are
+// serialized, so callbacks must not call Reload.
+func (w *Watcher) Reload(ctx context.Context) error {
+
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	var next = reflect.New(w.typ)
+	hash, err := loadHashed(next.Interface(), w.lo.file, w.opts)
+	if err != nil {
+		return fmt.Errorf("failed to reload config: %w", err)
+	}
+
+	w.mu.Lock()
+	var prev = w.current
+	w.current = next.Interface()
+	w.hash = hash
+	var subs = append([]subscription(nil), w.subs...)
+	w.mu.Unlock()
+
+	logger.FromContext(ctx).Info("config reloaded")
+
+	for _, sub := range subs {
+		var (
+			ov = lookupField(reflect.ValueOf(prev), sub.path, sub.fn.Type().In(0))
+			nv = lookupField(next, sub.path, sub.fn.Type().In(0))
+		)
+		if reflect.DeepEqual(ov.Interface(), nv.Interface()) {
+			continue
+		}
+		sub.fn.Call([]reflect.Value{ov, nv})
+	}
+
+	return nil
+}
+
+// Run watch config file changes until context is done. File system
+// notifications are used by default, or polling if poll interval is set.
+func (w *Watcher) Run(ctx context.Context) error {
+	if w.lo.pollInterval > 0 {
+		return w.poll(ctx)
+	}
+	return w.notify(ctx)
+}
+
+// poll check file hash with interval.
+func (w *Watcher) poll(ctx context.Context) error {
+
+	var ticker = time.NewTicker(w.lo.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			w.reloadChanged(ctx)
+		}
+	}
+}
+
+// notify wait for file system events in config directory. Directory is
+// watched to handle atomic file replacement (kubernetes config maps, etc).
+func (w *Watcher) notify(ctx context.Context) error {
+
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	defer fw.Close()
+
+	if err := fw.Add(filepath.Dir(w.lo.file)); err != nil {
+		return fmt.Errorf("failed to watch config directory: %w", err)
+	}
+
+	var debounce = time.NewTimer(debounceDelay)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-fw.Events:
+			if !ok {
+				return nil
+			}
+			debounce.Reset(debounceDelay)
+		case err, ok := <-fw.Errors:
+			if !ok {
+				return nil
+			}
+			logger.FromContext(ctx).WithErr(err).Error("config file watcher error")
+		case <-debounce.C:
+			w.reloadChanged(ctx)
+		}
+	}
+}
+
+// reloadChanged reload config if file content is changed.
+func (w *Watcher) reloadChanged(ctx context.Context) {
+
+	hash, err := fileHash(w.lo.file)
+	if err != nil {
+		logger.FromContext(ctx).WithErr(err).Error("failed to check config file")
+		return
+	}
+
+	w.mu.RLock()
+	var changed = !bytes.Equal(hash, w.hash)
+	w.mu.RUnlock()
+
+	if !changed {
+		return
+	}
+
+	if err := w.Reload(ctx); err != nil {
+		logger.FromContext(ctx).WithErr(err).Error("config reload error, current config is kept")
+	}
+}
+
+// lookupField return field value by path or zero value if path contains nil pointer.
+func lookupField(rv reflect.Value, path []string, ft reflect.Type) reflect.Value {
+	for _, name := range path {
+		if rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return reflect.Zero(ft)
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.FieldByName(name)
+	}
+	return rv
+}
+
+// loadHashed read config file once, load dst with its content and return
+// content hash, so hash always matches loaded config.
+func loadHashed(dst interface{}, path string, opts []loaderOption) ([]byte, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	opts = append(append([]loaderOption(nil), opts...), withFileData(data))
+	if err := Load(dst, opts...); err != nil {
+		return nil, err
+	}
+
+	var sum = sha256.Sum256(data)
+	return sum[:], nil
+}
+
+// fileHash calculate file content hash.
+func fileHash(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var sum = sha256.Sum256(data)
+	return sum[:], nil
+}
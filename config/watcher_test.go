@@ -0,0 +1,106 @@
+package config_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/config"
+	"github.com/tarusov/rig/logger"
+)
+
+func TestWatcherNotify(t *testing.T) {
+	testWatcher(t, false)
+}
+
+func TestWatcherPoll(t *testing.T) {
+	testWatcher(t, true)
+}
+
+// testWatcher check config file change notification.
+func testWatcher(t *testing.T, poll bool) {
+
+	var path = filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte("log_level: info\n"), 0600)
+	require.ErrorIsf(t, err, nil, "TestWatcher: unexpected write error: %v", err)
+
+	var cfg testConfig
+	var w *config.Watcher
+	if poll {
+		w, err = config.NewWatcher(&cfg, config.WithFile(path), config.WithoutEnv(), config.WithPollInterval(10*time.Millisecond))
+	} else {
+		w, err = config.NewWatcher(&cfg, config.WithFile(path), config.WithoutEnv())
+	}
+	require.ErrorIsf(t, err, nil, "TestWatcher: unexpected watcher error: %v", err)
+	require.Equalf(t, logger.LevelInfo, cfg.LogLevel, "TestWatcher: initial config not loaded")
+
+	err = w.Subscribe("LogLevel", func(old, new string) {})
+	require.Errorf(t, err, "TestWatcher: invalid callback accepted")
+
+	var changes = make(chan [2]logger.Level, 1)
+	err = w.Subscribe("LogLevel", func(old, new logger.Level) {
+		changes <- [2]logger.Level{old, new}
+	})
+	require.ErrorIsf(t, err, nil, "TestWatcher: unexpected subscribe error: %v", err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		_ = w.Run(ctx)
+	}()
+
+	// Give file watcher time to start.
+	time.Sleep(50 * time.Millisecond)
+
+	err = os.WriteFile(path, []byte("log_level: debug\n"), 0600)
+	require.ErrorIsf(t, err, nil, "TestWatcher: unexpected write error: %v", err)
+
+	select {
+	case change := <-changes:
+		require.Equalf(t, [2]logger.Level{logger.LevelInfo, logger.LevelDebug}, change, "TestWatcher: unexpected change")
+	case <-time.After(3 * time.Second):
+		t.Fatal("TestWatcher: change not notified")
+	}
+
+	require.Equalf(t, logger.LevelDebug, w.Current().(*testConfig).LogLevel, "TestWatcher: current config not replaced")
+}
+
+func TestWatcherConcurrentReload(t *testing.T) {
+
+	var path = filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte("log_level: info\n"), 0600)
+	require.ErrorIsf(t, err, nil, "TestWatcherConcurrentReload: unexpected write error: %v", err)
+
+	var cfg testConfig
+	w, err := config.NewWatcher(&cfg, config.WithFile(path), config.WithoutEnv())
+	require.ErrorIsf(t, err, nil, "TestWatcherConcurrentReload: unexpected watcher error: %v", err)
+
+	var changes int32
+	err = w.Subscribe("LogLevel", func(old, new logger.Level) {
+		atomic.AddInt32(&changes, 1)
+	})
+	require.ErrorIsf(t, err, nil, "TestWatcherConcurrentReload: unexpected subscribe error: %v", err)
+
+	err = os.WriteFile(path, []byte("log_level: debug\n"), 0600)
+	require.ErrorIsf(t, err, nil, "TestWatcherConcurrentReload: unexpected write error: %v", err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := w.Reload(context.Background())
+			assert.ErrorIsf(t, err, nil, "TestWatcherConcurrentReload: unexpected reload error: %v", err)
+		}()
+	}
+	wg.Wait()
+
+	require.Equalf(t, int32(1), atomic.LoadInt32(&changes), "TestWatcherConcurrentReload: change notified more than once")
+}
@@ -0,0 +1,29 @@
+package exec
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/oklog/run"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/tarusov/rig/metrics"
+)
+
+// Admin endpoints paths.
+const (
+	AdminHealthEndpoint  = "/health"
+	AdminMetricsEndpoint = "/metrics"
+	AdminVersionEndpoint = "/version"
+)
+
+// AddAdminEndpoints setup health, version and prometheus metrics endpoints on single port.
+func AddAdminEndpoints(ctx context.Context, g *run.Group, registry metrics.Registry, adminPort int) {
+
+	var mux = chi.NewMux()
+	mux.Handle(AdminHealthEndpoint, http.HandlerFunc(handleHealthEndpoint))
+	mux.Handle(AdminMetricsEndpoint, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	mux.Handle(AdminVersionEndpoint, http.HandlerFunc(handleVersionEndpoint))
+
+	addHTTPServer(ctx, g, "admin", adminPort, mux)
+}
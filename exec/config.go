@@ -0,0 +1,20 @@
+package exec
+
+import (
+	"context"
+
+	"github.com/oklog/run"
+	"github.com/tarusov/rig/config"
+)
+
+// AddConfigWatcher setup config file watcher, config is reloaded on file changes.
+func AddConfigWatcher(ctx context.Context, g *run.Group, w *config.Watcher) {
+
+	var wCtx, wCancel = context.WithCancel(ctx)
+
+	g.Add(func() error {
+		return w.Run(wCtx)
+	}, func(error) {
+		wCancel()
+	})
+}
@@ -0,0 +1,20 @@
+package exec
+
+import (
+	"context"
+
+	"github.com/oklog/run"
+	"github.com/tarusov/rig/featureflag"
+)
+
+// AddFeatureFlags setup feature flags refresh.
+func AddFeatureFlags(ctx context.Context, g *run.Group, c *featureflag.Client) {
+
+	var fCtx, fCancel = context.WithCancel(ctx)
+
+	g.Add(func() error {
+		return c.Run(fCtx)
+	}, func(error) {
+		fCancel()
+	})
+}
@@ -0,0 +1,52 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/oklog/run"
+	"github.com/tarusov/rig/logger"
+	"google.golang.org/grpc"
+)
+
+// defaultGRPCStopTimeout is max graceful stop duration, server will be stopped forcibly after.
+const defaultGRPCStopTimeout = 30 * time.Second
+
+// AddGRPCServer setup grpc server. Server is stopped gracefully on interrupt.
+func AddGRPCServer(ctx context.Context, g *run.Group, server *grpc.Server, grpcPort int) {
+
+	g.Add(func() error {
+
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", grpcPort))
+		if err != nil {
+			logger.FromContext(ctx).WithErr(err).Error("grpc server listen error")
+			return err
+		}
+
+		if err := server.Serve(listener); err != nil && err != grpc.ErrServerStopped {
+			logger.FromContext(ctx).WithErr(err).Error("grpc server serve error")
+			return err
+		}
+
+		logger.FromContext(ctx).Info("grpc server stopped")
+		return nil
+
+	}, func(error) {
+
+		var stopped = make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+			logger.FromContext(ctx).Info("grpc server interrupted")
+		case <-time.After(defaultGRPCStopTimeout):
+			server.Stop()
+			logger.FromContext(ctx).Warn("grpc server graceful stop timeout, stopped forcibly")
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package exec_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/oklog/run"
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/exec"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestGRPCServer(t *testing.T) {
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		g           = run.Group{}
+		server      = grpc.NewServer()
+		done        = make(chan error, 1)
+	)
+
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+
+	AddTestInterrupter(ctx, &g)
+	exec.AddGRPCServer(ctx, &g, server, 35001)
+
+	go func() {
+		done <- g.Run()
+	}()
+
+	dialCtx, dialCancel := context.WithTimeout(ctx, 3*time.Second)
+	defer dialCancel()
+
+	conn, err := grpc.DialContext(dialCtx, "localhost:35001", grpc.WithInsecure(), grpc.WithBlock())
+	require.ErrorIsf(t, err, nil, "TestGRPCServer: dial unexpected error: %v", err)
+	defer conn.Close()
+
+	resp, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	require.ErrorIsf(t, err, nil, "TestGRPCServer: check unexpected error: %v", err)
+	require.Equalf(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.GetStatus(), "TestGRPCServer: unexpected status")
+
+	cancel()
+	require.ErrorIsf(t, <-done, ErrTestTerminated, "TestGRPCServer: group termination unexpected error")
+}
@@ -0,0 +1,21 @@
+package exec
+
+import (
+	"context"
+
+	"github.com/oklog/run"
+	"github.com/tarusov/rig/leader"
+)
+
+// AddLeaderActor setup func which runs only while instance is leader.
+// Leadership is resigned on interrupt.
+func AddLeaderActor(ctx context.Context, g *run.Group, e *leader.Elector, fn leader.RunFunc) {
+
+	var lCtx, lCancel = context.WithCancel(ctx)
+
+	g.Add(func() error {
+		return e.Run(lCtx, fn)
+	}, func(error) {
+		lCancel()
+	})
+}
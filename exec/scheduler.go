@@ -0,0 +1,20 @@
+package exec
+
+import (
+	"context"
+
+	"github.com/oklog/run"
+	"github.com/tarusov/rig/scheduler"
+)
+
+// AddScheduler setup jobs scheduler. Running jobs are cancelled on interrupt.
+func AddScheduler(ctx context.Context, g *run.Group, s *scheduler.Scheduler) {
+
+	var sCtx, sCancel = context.WithCancel(ctx)
+
+	g.Add(func() error {
+		return s.Run(sCtx)
+	}, func(error) {
+		sCancel()
+	})
+}
@@ -0,0 +1,31 @@
+package exec
+
+import (
+	"context"
+	"sync"
+
+	"github.com/oklog/run"
+	"github.com/tarusov/rig/secrets"
+)
+
+// AddSecretsRefresher setup periodic secrets refresh, rotation callbacks are called on changes.
+func AddSecretsRefresher(ctx context.Context, g *run.Group, list ...*secrets.Secret) {
+
+	var sCtx, sCancel = context.WithCancel(ctx)
+
+	g.Add(func() error {
+		var wg sync.WaitGroup
+		for _, s := range list {
+			wg.Add(1)
+			go func(s *secrets.Secret) {
+				defer wg.Done()
+				_ = s.Run(sCtx)
+			}(s)
+		}
+		<-sCtx.Done()
+		wg.Wait()
+		return nil
+	}, func(error) {
+		sCancel()
+	})
+}
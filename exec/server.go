@@ -0,0 +1,42 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/oklog/run"
+	"github.com/tarusov/rig/logger"
+)
+
+// addHTTPServer setup http server actor with graceful shutdown on interrupt.
+func addHTTPServer(ctx context.Context, g *run.Group, name string, port int, handler http.Handler) {
+
+	var (
+		log    = logger.FromContext(ctx).WithField("server", name)
+		server = http.Server{
+			Addr:    fmt.Sprintf(":%d", port),
+			Handler: handler,
+		}
+	)
+
+	g.Add(func() error {
+
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.WithErr(err).Error("http server listen and serve error")
+			return err
+		}
+
+		log.Info("http server stopped")
+		return nil
+
+	}, func(error) {
+
+		if err := server.Shutdown(context.Background()); err != nil {
+			log.WithErr(err).Error("http server shutdown error")
+			return
+		}
+
+		log.Info("http server interrupted")
+	})
+}
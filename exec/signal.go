@@ -3,33 +3,109 @@ package exec
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"runtime/pprof"
 	"syscall"
 
 	"github.com/oklog/run"
 	"github.com/tarusov/rig/logger"
 )
 
+// SignalHandlerFunc is signal callback, which does not stop run group.
+type SignalHandlerFunc func(ctx context.Context) error
+
 // AddSignalWatcher setup a signal recivier for run group.
+// Group is terminated on SIGINT and SIGTERM.
 func AddSignalWatcher(ctx context.Context, g *run.Group) {
+	AddSignalHandler(ctx, g)
+}
+
+// AddSignalHandler setup configurable signal recivier for run group. Group is
+// terminated on terminate signals (SIGINT and SIGTERM by default), other
+// signals run handlers without group termination.
+func AddSignalHandler(ctx context.Context, g *run.Group, opts ...signalOption) {
+
+	var so = &signalOptions{
+		terminate: []os.Signal{syscall.SIGINT, syscall.SIGTERM},
+		handlers:  make(map[os.Signal]SignalHandlerFunc),
+	}
+
+	for _, opt := range opts {
+		opt(so)
+	}
 
 	var sCtx, sCancel = context.WithCancel(ctx)
 
 	g.Add(func() error {
 
-		sigChan := make(chan os.Signal, 2)
-		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+		var (
+			sigChan   = make(chan os.Signal, 2)
+			terminate = make(map[os.Signal]struct{}, len(so.terminate))
+			signals   = make([]os.Signal, 0, len(so.terminate)+len(so.handlers))
+		)
+
+		for _, sig := range so.terminate {
+			terminate[sig] = struct{}{}
+			signals = append(signals, sig)
+		}
+		for sig := range so.handlers {
+			signals = append(signals, sig)
+		}
+
+		signal.Notify(sigChan, signals...)
+		defer signal.Stop(sigChan)
 		logger.FromContext(sCtx).Info("signal watcher started")
 
-		select {
-		case c := <-sigChan:
-			return fmt.Errorf("terminated with sig %q", c)
-		case <-sCtx.Done():
-			return nil
+		for {
+			select {
+			case sig := <-sigChan:
+				if _, ok := terminate[sig]; ok {
+					return fmt.Errorf("terminated with sig %q", sig)
+				}
+
+				var log = logger.FromContext(sCtx).WithField("signal", sig)
+				if err := so.handlers[sig](sCtx); err != nil {
+					log.WithErr(err).Error("signal handler error")
+					continue
+				}
+				log.Info("signal handled")
+
+			case <-sCtx.Done():
+				return nil
+			}
 		}
 
 	}, func(err error) {
 		sCancel()
 	})
 }
+
+// DumpGoroutines create signal handler which write all goroutines stacks into w.
+func DumpGoroutines(w io.Writer) SignalHandlerFunc {
+	return func(context.Context) error {
+		return pprof.Lookup("goroutine").WriteTo(w, 2)
+	}
+}
+
+// ToggleDebugLevel create signal handler which switch logger to debug level
+// and back to previous level on next call.
+func ToggleDebugLevel(l *logger.Logger) SignalHandlerFunc {
+
+	var prev = l.LoggingLevel()
+	if prev == logger.LevelDebug {
+		prev = logger.LevelInfo
+	}
+
+	return func(context.Context) error {
+		if current := l.LoggingLevel(); current != logger.LevelDebug {
+			prev = current
+			l.SetLoggingLevel(logger.LevelDebug)
+			return nil
+		}
+
+		l.SetLoggingLevel(prev)
+		return nil
+	}
+}
@@ -0,0 +1,33 @@
+package exec
+
+import "os"
+
+type (
+	// signalOption is signal handler optional modificator.
+	signalOption func(*signalOptions)
+
+	// signalOptions is auxilary constructor struct.
+	signalOptions struct {
+		terminate []os.Signal
+		handlers  map[os.Signal]SignalHandlerFunc
+	}
+)
+
+// WithTerminateSignals replace list of signals which terminate run group.
+func WithTerminateSignals(sigs ...os.Signal) signalOption {
+	return func(so *signalOptions) {
+		so.terminate = append([]os.Signal(nil), sigs...)
+	}
+}
+
+// WithSignalHandler setup handler for signal (SIGHUP for config reload, SIGUSR1
+// for goroutines dump, etc). Signal does not terminate run group. Nil handler
+// is ignored.
+func WithSignalHandler(sig os.Signal, fn SignalHandlerFunc) signalOption {
+	return func(so *signalOptions) {
+		if fn == nil {
+			return
+		}
+		so.handlers[sig] = fn
+	}
+}
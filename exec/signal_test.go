@@ -0,0 +1,82 @@
+package exec_test
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/oklog/run"
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/exec"
+	"github.com/tarusov/rig/logger"
+)
+
+func TestSignalHandler(t *testing.T) {
+
+	// Prevent process termination before handler setup.
+	var guard = make(chan os.Signal, 1)
+	signal.Notify(guard, syscall.SIGHUP, syscall.SIGUSR2)
+	defer signal.Stop(guard)
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		g           = run.Group{}
+		reloaded    = make(chan struct{}, 1)
+		done        = make(chan error, 1)
+	)
+
+	AddTestInterrupter(ctx, &g)
+	exec.AddSignalHandler(context.Background(), &g, exec.WithSignalHandler(syscall.SIGHUP, func(context.Context) error {
+		select {
+		case reloaded <- struct{}{}:
+		default:
+		}
+		return nil
+	}), exec.WithSignalHandler(syscall.SIGUSR2, nil))
+
+	go func() {
+		done <- g.Run()
+	}()
+
+	var timeout = time.After(3 * time.Second)
+	for called := false; !called; {
+		require.ErrorIsf(t, syscall.Kill(os.Getpid(), syscall.SIGHUP), nil, "TestSignalHandler: send signal unexpected error")
+		select {
+		case <-reloaded:
+			called = true
+		case <-time.After(10 * time.Millisecond):
+		case <-timeout:
+			t.Fatal("TestSignalHandler: signal handler not called")
+		}
+	}
+
+	// Signal with nil handler is not watched.
+	require.ErrorIsf(t, syscall.Kill(os.Getpid(), syscall.SIGUSR2), nil, "TestSignalHandler: send signal unexpected error")
+	time.Sleep(10 * time.Millisecond)
+
+	select {
+	case err := <-done:
+		t.Fatalf("TestSignalHandler: group terminated by handled signal: %v", err)
+	default:
+	}
+
+	cancel()
+	require.ErrorIsf(t, <-done, ErrTestTerminated, "TestSignalHandler: group termination unexpected error")
+}
+
+func TestToggleDebugLevel(t *testing.T) {
+
+	var (
+		log    = logger.New(logger.WithLoggingLevel(logger.LevelWarning))
+		toggle = exec.ToggleDebugLevel(log)
+	)
+
+	require.ErrorIsf(t, toggle(context.Background()), nil, "TestToggleDebugLevel: unexpected error")
+	require.Equalf(t, logger.LevelDebug, log.LoggingLevel(), "TestToggleDebugLevel: debug level not set")
+
+	require.ErrorIsf(t, toggle(context.Background()), nil, "TestToggleDebugLevel: unexpected error")
+	require.Equalf(t, logger.LevelWarning, log.LoggingLevel(), "TestToggleDebugLevel: previous level not restored")
+}
@@ -0,0 +1,29 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/oklog/run"
+	"github.com/tarusov/rig/buildinfo"
+	"github.com/tarusov/rig/logger"
+)
+
+// handleVersionEndpoint write build information response.
+func handleVersionEndpoint(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(buildinfo.Get()); err != nil {
+		logger.FromContext(r.Context()).WithErr(err).Error("failed to write version response")
+	}
+}
+
+// AddVersionEndpoint setup build information endpoint.
+func AddVersionEndpoint(ctx context.Context, g *run.Group, enpoint string, versionPort int) {
+
+	var mux = chi.NewMux()
+	mux.Handle(enpoint, http.HandlerFunc(handleVersionEndpoint))
+
+	addHTTPServer(ctx, g, "version", versionPort, mux)
+}
@@ -0,0 +1,51 @@
+package exec_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/oklog/run"
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/buildinfo"
+	"github.com/tarusov/rig/exec"
+)
+
+func TestVersionEndpointHandler(t *testing.T) {
+
+	buildinfo.Version = "v1.2.3"
+	defer func() { buildinfo.Version = "" }()
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		g           = run.Group{}
+	)
+	defer cancel()
+
+	AddTestInterrupter(ctx, &g)
+	exec.AddVersionEndpoint(ctx, &g, "/version", 35002)
+
+	go func() {
+		_ = g.Run()
+	}()
+
+	var (
+		httpResp *http.Response
+		err      error
+	)
+	for i := 0; i < 50; i++ {
+		if httpResp, err = http.Get("http://localhost:35002/version"); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.ErrorIsf(t, err, nil, "TestVersionEndpointHandler: send request unexpected error: %v", err)
+	defer httpResp.Body.Close()
+
+	var info buildinfo.Info
+	err = json.NewDecoder(httpResp.Body).Decode(&info)
+	require.ErrorIsf(t, err, nil, "TestVersionEndpointHandler: decode response unexpected error: %v", err)
+	require.Equalf(t, "v1.2.3", info.Version, "TestVersionEndpointHandler: unexpected version")
+}
@@ -0,0 +1,20 @@
+package exec
+
+import (
+	"context"
+
+	"github.com/oklog/run"
+	"github.com/tarusov/rig/workerpool"
+)
+
+// AddWorkerPool setup background tasks pool. Queued tasks are drained on interrupt.
+func AddWorkerPool(ctx context.Context, g *run.Group, p *workerpool.Pool) {
+
+	var pCtx, pCancel = context.WithCancel(ctx)
+
+	g.Add(func() error {
+		return p.Run(pCtx)
+	}, func(error) {
+		pCancel()
+	})
+}
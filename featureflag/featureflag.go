@@ -0,0 +1,226 @@
+// Package featureflag contains feature flags client with file and redis backends.
+package featureflag
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tarusov/rig/logger"
+	"github.com/tarusov/rig/metrics"
+)
+
+type (
+	// Client struct.
+	Client struct {
+		provider Provider
+
+		mu    sync.RWMutex
+		flags map[string]Flag
+		subs  []ChangeFunc
+
+		stickiness      string
+		refreshInterval time.Duration
+		evaluations     metrics.Count
+	}
+
+	// Provider is flags storage backend.
+	Provider interface {
+		// Load return all flags by key.
+		Load(ctx context.Context) (map[string]Flag, error)
+	}
+
+	// Watcher is optional provider interface for change notifications.
+	Watcher interface {
+		// Watch call notify on flags change until context is done.
+		Watch(ctx context.Context, notify func()) error
+	}
+
+	// ChangeFunc is flag change callback, old or new flag is nil for added or removed flags.
+	ChangeFunc func(key string, old, new *Flag)
+)
+
+// Defaults.
+const (
+	defaultStickiness      = "id"
+	defaultRefreshInterval = 30 * time.Second
+)
+
+// Evaluation results for missing flags.
+const resultDefault = "default"
+
+// New creates new client instance and load flags from provider.
+func New(ctx context.Context, provider Provider, opts ...clientOption) (*Client, error) {
+
+	var co = &clientOptions{
+		stickiness:      defaultStickiness,
+		refreshInterval: defaultRefreshInterval,
+	}
+
+	for _, opt := range opts {
+		opt(co)
+	}
+
+	var c = &Client{
+		provider:        provider,
+		flags:           make(map[string]Flag),
+		stickiness:      co.stickiness,
+		refreshInterval: co.refreshInterval,
+	}
+
+	if co.registry != nil {
+		var err error
+		c.evaluations, err = metrics.NewCount(co.registry,
+			"featureflag_evaluations_total",
+			"Total number of feature flag evaluations.",
+			"flag", "result",
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Bool evaluate boolean or percentage flag for context attributes.
+// Default value is returned for unknown flags.
+func (c *Client) Bool(ctx context.Context, key string, def bool) bool {
+
+	var f, ok = c.flag(key)
+	if !ok {
+		c.observe(key, resultDefault)
+		return def
+	}
+
+	var on = f.isOn(AttributesFromContext(ctx), c.stickiness)
+	c.observe(key, strconv.FormatBool(on))
+
+	return on
+}
+
+// Variant evaluate variant flag for context attributes.
+// Default value is returned for unknown or disabled flags.
+func (c *Client) Variant(ctx context.Context, key string, def string) string {
+
+	var f, ok = c.flag(key)
+	if !ok {
+		c.observe(key, resultDefault)
+		return def
+	}
+
+	var v = f.variant(AttributesFromContext(ctx), c.stickiness)
+	if v == "" {
+		c.observe(key, resultDefault)
+		return def
+	}
+	c.observe(key, v)
+
+	return v
+}
+
+// OnChange add flags change callback.
+func (c *Client) OnChange(fn ChangeFunc) {
+	c.mu.Lock()
+	c.subs = append(c.subs, fn)
+	c.mu.Unlock()
+}
+
+// Refresh reload flags from provider and notify subscribers about changes.
+func (c *Client) Refresh(ctx context.Context) error {
+
+	flags, err := c.provider.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	var prev = c.flags
+	c.flags = flags
+	var subs = append([]ChangeFunc(nil), c.subs...)
+	c.mu.Unlock()
+
+	if len(subs) == 0 {
+		return nil
+	}
+
+	for key, nf := range flags {
+		var nf = nf
+		if of, ok := prev[key]; !ok {
+			notify(subs, key, nil, &nf)
+		} else if !reflect.DeepEqual(of, nf) {
+			notify(subs, key, &of, &nf)
+		}
+	}
+	for key, of := range prev {
+		var of = of
+		if _, ok := flags[key]; !ok {
+			notify(subs, key, &of, nil)
+		}
+	}
+
+	return nil
+}
+
+// Run refresh flags periodically and on provider notifications until context is done.
+func (c *Client) Run(ctx context.Context) error {
+
+	var notified = make(chan struct{}, 1)
+	if w, ok := c.provider.(Watcher); ok {
+		go func() {
+			err := w.Watch(ctx, func() {
+				select {
+				case notified <- struct{}{}:
+				default:
+				}
+			})
+			if err != nil {
+				logger.FromContext(ctx).WithErr(err).Error("feature flags watch error")
+			}
+		}()
+	}
+
+	var ticker = time.NewTicker(c.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-notified:
+		}
+
+		if err := c.Refresh(ctx); err != nil {
+			logger.FromContext(ctx).WithErr(err).Error("feature flags refresh error, cached flags are used")
+		}
+	}
+}
+
+// flag return cached flag by key.
+func (c *Client) flag(key string) (Flag, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	f, ok := c.flags[key]
+	return f, ok
+}
+
+// observe write evaluation metric.
+func (c *Client) observe(key, result string) {
+	if c.evaluations != nil {
+		c.evaluations.WithLabelValues(key, result).Inc()
+	}
+}
+
+// notify call change callbacks.
+func notify(subs []ChangeFunc, key string, old, new *Flag) {
+	for _, fn := range subs {
+		fn(key, old, new)
+	}
+}
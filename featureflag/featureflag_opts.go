@@ -0,0 +1,40 @@
+package featureflag
+
+import (
+	"time"
+
+	"github.com/tarusov/rig/metrics"
+)
+
+type (
+	// clientOption is client constructor optional modificator.
+	clientOption func(*clientOptions)
+
+	// clientOptions is auxilary constructor struct.
+	clientOptions struct {
+		stickiness      string
+		refreshInterval time.Duration
+		registry        metrics.Registry
+	}
+)
+
+// WithStickiness setup attribute used for percentage and variant bucketing ("id" by default).
+func WithStickiness(attribute string) clientOption {
+	return func(co *clientOptions) {
+		co.stickiness = attribute
+	}
+}
+
+// WithRefreshInterval setup flags reload interval.
+func WithRefreshInterval(interval time.Duration) clientOption {
+	return func(co *clientOptions) {
+		co.refreshInterval = interval
+	}
+}
+
+// WithMetrics setup registry for evaluation metrics.
+func WithMetrics(registry metrics.Registry) clientOption {
+	return func(co *clientOptions) {
+		co.registry = registry
+	}
+}
@@ -0,0 +1,87 @@
+package featureflag_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/featureflag"
+	"github.com/tarusov/rig/metrics"
+)
+
+const testFlags = `
+flags:
+  - key: dark-mode
+    type: boolean
+    enabled: true
+  - key: new-checkout
+    type: percentage
+    enabled: true
+    percentage: 30
+    rules:
+      - attribute: country
+        values: [nl]
+  - key: button-color
+    type: variant
+    enabled: true
+    variants:
+      - name: red
+        weight: 1
+      - name: blue
+        weight: 1
+    rules:
+      - attribute: id
+        values: [admin]
+        variant: green
+`
+
+func TestClient(t *testing.T) {
+
+	var path = filepath.Join(t.TempDir(), "flags.yaml")
+	err := os.WriteFile(path, []byte(testFlags), 0600)
+	require.ErrorIsf(t, err, nil, "TestClient: unexpected write error: %v", err)
+
+	var ctx = context.Background()
+	c, err := featureflag.New(ctx, featureflag.NewFileProvider(path), featureflag.WithMetrics(metrics.New()))
+	require.ErrorIsf(t, err, nil, "TestClient: unexpected client error: %v", err)
+
+	require.Truef(t, c.Bool(ctx, "dark-mode", false), "TestClient: boolean flag is off")
+	require.Truef(t, c.Bool(ctx, "unknown", true), "TestClient: default value not returned")
+
+	var (
+		on       int
+		variants = make(map[string]int)
+	)
+	for i := 0; i < 1000; i++ {
+		var userCtx = featureflag.ContextWithAttributes(ctx, featureflag.Attributes{"id": strconv.Itoa(i)})
+		if c.Bool(userCtx, "new-checkout", false) {
+			on++
+		}
+		variants[c.Variant(userCtx, "button-color", "none")]++
+	}
+	require.InDeltaf(t, 300, on, 60, "TestClient: unexpected percentage rollout")
+	require.InDeltaf(t, 500, variants["red"], 60, "TestClient: unexpected variants distribution")
+	require.InDeltaf(t, 500, variants["blue"], 60, "TestClient: unexpected variants distribution")
+
+	var targeted = featureflag.ContextWithAttributes(ctx, featureflag.Attributes{"id": "admin", "country": "nl"})
+	require.Truef(t, c.Bool(targeted, "new-checkout", false), "TestClient: targeting rule not applied")
+	require.Equalf(t, "green", c.Variant(targeted, "button-color", "none"), "TestClient: variant rule not applied")
+
+	var changed []string
+	c.OnChange(func(key string, old, new *featureflag.Flag) {
+		changed = append(changed, key)
+		require.NotNilf(t, old, "TestClient: old flag is nil")
+		require.Falsef(t, new.Enabled, "TestClient: new flag is enabled")
+	})
+
+	err = os.WriteFile(path, []byte("flags:\n  - key: dark-mode\n    type: boolean\n  - key: new-checkout\n    type: percentage\n    percentage: 30\n    rules:\n      - attribute: country\n        values: [nl]\n  - key: button-color\n    type: variant\n    variants: [{name: red, weight: 1}, {name: blue, weight: 1}]\n    rules: [{attribute: id, values: [admin], variant: green}]\n"), 0600)
+	require.ErrorIsf(t, err, nil, "TestClient: unexpected write error: %v", err)
+
+	err = c.Refresh(ctx)
+	require.ErrorIsf(t, err, nil, "TestClient: unexpected refresh error: %v", err)
+	require.Lenf(t, changed, 3, "TestClient: changes not notified")
+	require.Falsef(t, c.Bool(ctx, "dark-mode", true), "TestClient: disabled flag is on")
+}
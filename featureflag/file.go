@@ -0,0 +1,62 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileProvider load flags from local yaml or json file:
+//
+//	flags:
+//	  - key: new-checkout
+//	    type: percentage
+//	    enabled: true
+//	    percentage: 25
+type FileProvider struct {
+	path string
+}
+
+// fileContent is flags file structure.
+type fileContent struct {
+	Flags []Flag `json:"flags" yaml:"flags"`
+}
+
+// NewFileProvider create new file provider. Format is defined by extension (.yaml, .yml, .json).
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{path: path}
+}
+
+// Load implements Provider Load method.
+func (fp *FileProvider) Load(_ context.Context) (map[string]Flag, error) {
+
+	data, err := os.ReadFile(fp.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read flags file: %w", err)
+	}
+
+	var content fileContent
+	switch strings.ToLower(filepath.Ext(fp.path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &content)
+	case ".json":
+		err = json.Unmarshal(data, &content)
+	default:
+		return nil, fmt.Errorf("unsupported flags file format: %q", fp.path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode flags file: %w", err)
+	}
+
+	var flags = make(map[string]Flag, len(content.Flags))
+	for _, f := range content.Flags {
+		flags[f.Key] = f
+	}
+
+	return flags, nil
+}
@@ -0,0 +1,134 @@
+package featureflag
+
+import (
+	"context"
+	"hash/fnv"
+)
+
+type (
+	// Flag is feature flag definition.
+	Flag struct {
+		Key        string    `json:"key" yaml:"key"`
+		Type       Type      `json:"type" yaml:"type"`
+		Enabled    bool      `json:"enabled" yaml:"enabled"`       // Master switch, disabled flag is always off.
+		Percentage float64   `json:"percentage" yaml:"percentage"` // Rollout percentage [0, 100] for percentage flags.
+		Variants   []Variant `json:"variants" yaml:"variants"`     // Weighted variants for variant flags.
+		Rules      []Rule    `json:"rules" yaml:"rules"`           // Targeting rules, first matched rule wins.
+	}
+
+	// Type is flag evaluation type.
+	Type string
+
+	// Variant is weighted flag variant.
+	Variant struct {
+		Name   string `json:"name" yaml:"name"`
+		Weight int    `json:"weight" yaml:"weight"`
+	}
+
+	// Rule is targeting rule. It matches if attribute value is in values list.
+	Rule struct {
+		Attribute string   `json:"attribute" yaml:"attribute"`
+		Values    []string `json:"values" yaml:"values"`
+		Enabled   *bool    `json:"enabled,omitempty" yaml:"enabled,omitempty"` // Result for boolean and percentage flags, true if not set.
+		Variant   string   `json:"variant,omitempty" yaml:"variant,omitempty"` // Result for variant flags.
+	}
+
+	// Attributes is evaluation context attributes (user id, country, etc).
+	Attributes map[string]string
+
+	// attributesContextKey is custom context key for attributes.
+	attributesContextKey struct{}
+)
+
+// Type type enum.
+const (
+	TypeBoolean    Type = "boolean"
+	TypePercentage Type = "percentage"
+	TypeVariant    Type = "variant"
+)
+
+// ContextWithAttributes insert evaluation attributes into context.
+func ContextWithAttributes(ctx context.Context, attrs Attributes) context.Context {
+	return context.WithValue(ctx, attributesContextKey{}, attrs)
+}
+
+// AttributesFromContext extract evaluation attributes from context.
+func AttributesFromContext(ctx context.Context) Attributes {
+	if ctx != nil {
+		if v, ok := ctx.Value(attributesContextKey{}).(Attributes); ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// match return first matched targeting rule.
+func (f *Flag) match(attrs Attributes) *Rule {
+	for i := range f.Rules {
+		var v, ok = attrs[f.Rules[i].Attribute]
+		if !ok {
+			continue
+		}
+		for _, rv := range f.Rules[i].Values {
+			if rv == v {
+				return &f.Rules[i]
+			}
+		}
+	}
+	return nil
+}
+
+// isOn evaluate boolean or percentage flag.
+func (f *Flag) isOn(attrs Attributes, stickiness string) bool {
+
+	if !f.Enabled {
+		return false
+	}
+
+	if rule := f.match(attrs); rule != nil {
+		return rule.Enabled == nil || *rule.Enabled
+	}
+
+	if f.Type == TypePercentage {
+		return bucket(f.Key, attrs[stickiness]) < f.Percentage
+	}
+
+	return true
+}
+
+// variant evaluate variant flag, empty string is returned if no variant selected.
+func (f *Flag) variant(attrs Attributes, stickiness string) string {
+
+	if !f.Enabled {
+		return ""
+	}
+
+	if rule := f.match(attrs); rule != nil && rule.Variant != "" {
+		return rule.Variant
+	}
+
+	var total int
+	for _, v := range f.Variants {
+		total += v.Weight
+	}
+	if total <= 0 {
+		return ""
+	}
+
+	var point = bucket(f.Key, attrs[stickiness]) / 100 * float64(total)
+	for _, v := range f.Variants {
+		if point < float64(v.Weight) {
+			return v.Name
+		}
+		point -= float64(v.Weight)
+	}
+
+	return f.Variants[len(f.Variants)-1].Name
+}
+
+// bucket return stable value in [0, 100) for flag and stickiness attribute value.
+func bucket(key, id string) float64 {
+	var h = fnv.New32a()
+	_, _ = h.Write([]byte(key + ":" + id))
+	return float64(h.Sum32()%10000) / 100
+}
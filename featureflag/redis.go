@@ -0,0 +1,131 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/tarusov/rig/logger"
+)
+
+// RedisProvider load flags from redis hash, field is flag key and value is
+// flag json. Changes made with SetFlag and DeleteFlag are published into
+// notifications channel.
+type RedisProvider struct {
+	client  redis.UniversalClient
+	key     string
+	channel string
+}
+
+const (
+	// notificationsSuffix is suffix of changes channel name.
+	notificationsSuffix = ":changes"
+	// resubscribeInterval is delay between subscription attempts.
+	resubscribeInterval = time.Second
+)
+
+// NewRedisProvider create new redis provider for hash key.
+func NewRedisProvider(client redis.UniversalClient, key string) *RedisProvider {
+	return &RedisProvider{
+		client:  client,
+		key:     key,
+		channel: key + notificationsSuffix,
+	}
+}
+
+// Load implements Provider Load method.
+func (rp *RedisProvider) Load(ctx context.Context) (map[string]Flag, error) {
+
+	values, err := rp.client.HGetAll(ctx, rp.key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load flags: %w", err)
+	}
+
+	var flags = make(map[string]Flag, len(values))
+	for key, value := range values {
+		var f Flag
+		if err := json.Unmarshal([]byte(value), &f); err != nil {
+			return nil, fmt.Errorf("failed to decode flag %q: %w", key, err)
+		}
+		f.Key = key
+		flags[key] = f
+	}
+
+	return flags, nil
+}
+
+// Watch implements Watcher Watch method. Subscription is restored on errors,
+// notify is called after resubscribe because changes may be missed.
+func (rp *RedisProvider) Watch(ctx context.Context, notify func()) error {
+
+	var subscribed bool
+	for {
+		err := rp.watch(ctx, notify, &subscribed)
+		if ctx.Err() != nil {
+			return nil
+		}
+		logger.FromContext(ctx).WithErr(err).Warn("feature flags subscription lost")
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(resubscribeInterval):
+		}
+	}
+}
+
+// watch subscribe to changes and call notify until subscription fails.
+func (rp *RedisProvider) watch(ctx context.Context, notify func(), subscribed *bool) error {
+
+	var sub = rp.client.Subscribe(ctx, rp.channel)
+	defer sub.Close()
+
+	if _, err := sub.Receive(ctx); err != nil {
+		return fmt.Errorf("failed to subscribe flags changes: %w", err)
+	}
+	if *subscribed {
+		notify()
+	}
+	*subscribed = true
+
+	var ch = sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-ch:
+			if !ok {
+				return errors.New("flags changes channel closed")
+			}
+			notify()
+		}
+	}
+}
+
+// SetFlag store flag and notify watchers.
+func (rp *RedisProvider) SetFlag(ctx context.Context, f Flag) error {
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to encode flag: %w", err)
+	}
+
+	if err := rp.client.HSet(ctx, rp.key, f.Key, data).Err(); err != nil {
+		return fmt.Errorf("failed to store flag: %w", err)
+	}
+
+	return rp.client.Publish(ctx, rp.channel, f.Key).Err()
+}
+
+// DeleteFlag remove flag and notify watchers.
+func (rp *RedisProvider) DeleteFlag(ctx context.Context, key string) error {
+
+	if err := rp.client.HDel(ctx, rp.key, key).Err(); err != nil {
+		return fmt.Errorf("failed to delete flag: %w", err)
+	}
+
+	return rp.client.Publish(ctx, rp.channel, key).Err()
+}
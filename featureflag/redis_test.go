@@ -0,0 +1,106 @@
+package featureflag_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/featureflag"
+	"github.com/tarusov/rig/internal/testcontainer"
+)
+
+// redisURI for tests. External server may be set with REDIS_URI env.
+var redisURI string
+
+func TestMain(m *testing.M) {
+
+	uri, terminate, err := testcontainer.Redis(context.Background())
+	if err != nil {
+		log.Println("redis container not inited", err)
+	}
+	redisURI = uri
+
+	var code = m.Run()
+	terminate()
+	os.Exit(code)
+}
+
+func TestRedisProvider(t *testing.T) {
+
+	if redisURI == "" {
+		t.Skip("redis is not available")
+	}
+
+	opts, err := redis.ParseURL(redisURI)
+	require.ErrorIsf(t, err, nil, "TestRedisProvider: unexpected parse url error: %v", err)
+
+	var client = redis.NewClient(opts)
+	defer client.Close()
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		rp          = featureflag.NewRedisProvider(client, "flags:"+uuid.NewString())
+		notified    = make(chan struct{}, 1)
+		done        = make(chan error, 1)
+	)
+	defer cancel()
+
+	go func() {
+		done <- rp.Watch(ctx, func() {
+			select {
+			case notified <- struct{}{}:
+			default:
+			}
+		})
+	}()
+	time.Sleep(100 * time.Millisecond) // Wait for subscription.
+
+	err = rp.SetFlag(ctx, featureflag.Flag{Key: "dark-mode", Type: featureflag.TypeBoolean, Enabled: true})
+	require.ErrorIsf(t, err, nil, "TestRedisProvider: unexpected set error: %v", err)
+
+	select {
+	case <-notified:
+	case <-time.After(time.Second):
+		require.FailNow(t, "TestRedisProvider: set flag not notified")
+	}
+
+	flags, err := rp.Load(ctx)
+	require.ErrorIsf(t, err, nil, "TestRedisProvider: unexpected load error: %v", err)
+	require.Lenf(t, flags, 1, "TestRedisProvider: unexpected flags count")
+	require.Truef(t, flags["dark-mode"].Enabled, "TestRedisProvider: flag not stored")
+
+	err = rp.DeleteFlag(ctx, "dark-mode")
+	require.ErrorIsf(t, err, nil, "TestRedisProvider: unexpected delete error: %v", err)
+
+	select {
+	case <-notified:
+	case <-time.After(time.Second):
+		require.FailNow(t, "TestRedisProvider: delete flag not notified")
+	}
+
+	flags, err = rp.Load(ctx)
+	require.ErrorIsf(t, err, nil, "TestRedisProvider: unexpected load error: %v", err)
+	require.Emptyf(t, flags, "TestRedisProvider: flag not deleted")
+
+	cancel()
+	require.ErrorIsf(t, <-done, nil, "TestRedisProvider: unexpected watch error")
+}
+
+func TestRedisProviderWatchUnavailable(t *testing.T) {
+
+	var client = redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	var rp = featureflag.NewRedisProvider(client, "flags")
+	err := rp.Watch(ctx, func() {})
+	require.ErrorIsf(t, err, nil, "TestRedisProviderWatchUnavailable: unexpected watch error: %v", err)
+	require.ErrorIsf(t, ctx.Err(), context.DeadlineExceeded, "TestRedisProviderWatchUnavailable: watch returned before context done")
+}
@@ -0,0 +1,63 @@
+// Package grpcmw contains grpc server and client interceptors.
+package grpcmw
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataRequestID is request id metadata key.
+const MetadataRequestID = "x-request-id"
+
+// serverStream wrap grpc.ServerStream with custom context.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context implements grpc.ServerStream Context method.
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// wrapServerStream replace stream context.
+func wrapServerStream(ss grpc.ServerStream, ctx context.Context) grpc.ServerStream {
+	if ws, ok := ss.(*serverStream); ok {
+		ws.ctx = ctx
+		return ws
+	}
+	return &serverStream{ServerStream: ss, ctx: ctx}
+}
+
+// splitMethod split full method name (/package.service/method) into service and method names.
+func splitMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", fullMethod
+}
+
+// metadataCarrier implements opentracing TextMapReader and TextMapWriter for grpc metadata.
+type metadataCarrier metadata.MD
+
+// Set implements opentracing.TextMapWriter Set method.
+func (mc metadataCarrier) Set(key, val string) {
+	key = strings.ToLower(key)
+	mc[key] = append(mc[key], val)
+}
+
+// ForeachKey implements opentracing.TextMapReader ForeachKey method.
+func (mc metadataCarrier) ForeachKey(handler func(key, val string) error) error {
+	for k, vs := range mc {
+		for _, v := range vs {
+			if err := handler(k, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
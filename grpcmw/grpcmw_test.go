@@ -0,0 +1,105 @@
+package grpcmw_test
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/grpcmw"
+	"github.com/tarusov/rig/logger"
+	"github.com/tarusov/rig/metrics"
+	"github.com/tarusov/rig/validator"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestInterceptors(t *testing.T) {
+
+	var (
+		ctx      = context.Background()
+		buf      = bytes.NewBuffer(make([]byte, 0))
+		log      = logger.New(logger.WithLoggingOutput(buf))
+		tracer   = mocktracer.New()
+		registry = metrics.New()
+		listener = bufconn.Listen(1024 * 1024)
+	)
+
+	serverMetrics, err := grpcmw.UnaryServerMetrics(registry)
+	require.ErrorIsf(t, err, nil, "TestInterceptors: unexpected metrics error: %v", err)
+
+	var server = grpc.NewServer(grpc.ChainUnaryInterceptor(
+		grpcmw.UnaryServerLogger(log),
+		grpcmw.UnaryServerTracing(tracer),
+		serverMetrics,
+		grpcmw.UnaryServerValidation(),
+	))
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(grpcmw.UnaryClientTracing(tracer)),
+	)
+	require.ErrorIsf(t, err, nil, "TestInterceptors: unexpected dial error: %v", err)
+	defer conn.Close()
+
+	var callCtx = metadata.AppendToOutgoingContext(ctx, grpcmw.MetadataRequestID, "grpc-request")
+	_, err = grpc_health_v1.NewHealthClient(conn).Check(callCtx, &grpc_health_v1.HealthCheckRequest{})
+	require.ErrorIsf(t, err, nil, "TestInterceptors: unexpected call error: %v", err)
+
+	require.Containsf(t, buf.String(), "grpc-request", "TestInterceptors: request id not logged")
+	require.Containsf(t, buf.String(), "Check", "TestInterceptors: method not logged")
+
+	var spans = tracer.FinishedSpans()
+	require.Lenf(t, spans, 2, "TestInterceptors: unexpected spans count")
+	require.Equalf(t, spans[1].SpanContext.TraceID, spans[0].SpanContext.TraceID, "TestInterceptors: span context not propagated")
+
+	families, err := registry.Gather()
+	require.ErrorIsf(t, err, nil, "TestInterceptors: unexpected gather error: %v", err)
+
+	var found bool
+	for _, mf := range families {
+		if mf.GetName() == "grpc_server_handling_seconds" {
+			found = true
+		}
+	}
+	require.Truef(t, found, "TestInterceptors: server metric not recorded")
+}
+
+func TestValidationStatus(t *testing.T) {
+
+	var interceptor = grpcmw.UnaryServerValidation()
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{},
+		func(context.Context, interface{}) (interface{}, error) {
+			return nil, validator.FieldErrors{
+				"name":  "name is a required field",
+				"email": "email must be a valid email address",
+			}
+		},
+	)
+
+	var st = status.Convert(err)
+	require.Equalf(t, codes.InvalidArgument, st.Code(), "TestValidationStatus: unexpected code")
+	require.Lenf(t, st.Details(), 1, "TestValidationStatus: unexpected details count")
+
+	br, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.Truef(t, ok, "TestValidationStatus: unexpected details type %T", st.Details()[0])
+	require.Lenf(t, br.GetFieldViolations(), 2, "TestValidationStatus: unexpected violations count")
+	require.Equalf(t, "email", br.GetFieldViolations()[0].GetField(), "TestValidationStatus: violations not sorted")
+}
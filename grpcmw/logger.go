@@ -0,0 +1,78 @@
+package grpcmw
+
+import (
+	"context"
+	"time"
+
+	"github.com/tarusov/rig/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerLogger interceptor put request scoped logger into context and log completed calls.
+func UnaryServerLogger(log *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+
+		var (
+			start  = time.Now()
+			reqLog = requestLogger(ctx, log, info.FullMethod)
+		)
+
+		resp, err := handler(logger.ContextWithLogger(ctx, reqLog), req)
+		logCompleted(reqLog, start, err)
+
+		return resp, err
+	}
+}
+
+// StreamServerLogger interceptor put stream scoped logger into context and log completed streams.
+func StreamServerLogger(log *logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		var (
+			start  = time.Now()
+			ctx    = ss.Context()
+			reqLog = requestLogger(ctx, log, info.FullMethod)
+		)
+
+		err := handler(srv, wrapServerStream(ss, logger.ContextWithLogger(ctx, reqLog)))
+		logCompleted(reqLog, start, err)
+
+		return err
+	}
+}
+
+// requestLogger create logger with call fields.
+func requestLogger(ctx context.Context, log *logger.Logger, fullMethod string) *logger.Logger {
+
+	var service, method = splitMethod(fullMethod)
+	var reqLog = log.WithFields(logger.Fields{
+		"grpc_service": service,
+		"grpc_method":  method,
+	})
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vs := md.Get(MetadataRequestID); len(vs) != 0 {
+			reqLog = reqLog.WithField(logger.FieldNameRequestID, vs[0])
+		}
+	}
+
+	return reqLog
+}
+
+// logCompleted write call result.
+func logCompleted(log *logger.Logger, start time.Time, err error) {
+
+	var event = log.WithFields(logger.Fields{
+		"grpc_code": status.Code(err).String(),
+		"duration":  time.Since(start),
+	}).WithErr(err)
+
+	if err != nil {
+		event.Error("call completed")
+		return
+	}
+
+	event.Info("call completed")
+}
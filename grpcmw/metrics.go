@@ -0,0 +1,101 @@
+package grpcmw
+
+import (
+	"context"
+	"time"
+
+	"github.com/tarusov/rig/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// metricsLabels is list of call metrics labels.
+var metricsLabels = []string{"grpc_service", "grpc_method", "grpc_code"}
+
+// UnaryServerMetrics interceptor record per-method handling time histogram.
+func UnaryServerMetrics(registry metrics.Registry) (grpc.UnaryServerInterceptor, error) {
+
+	duration, err := serverDuration(registry)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var start = time.Now()
+		resp, err := handler(ctx, req)
+		observe(duration, info.FullMethod, start, err)
+		return resp, err
+	}, nil
+}
+
+// StreamServerMetrics interceptor record per-method handling time histogram.
+func StreamServerMetrics(registry metrics.Registry) (grpc.StreamServerInterceptor, error) {
+
+	duration, err := serverDuration(registry)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		var start = time.Now()
+		err := handler(srv, ss)
+		observe(duration, info.FullMethod, start, err)
+		return err
+	}, nil
+}
+
+// UnaryClientMetrics interceptor record per-method call time histogram.
+func UnaryClientMetrics(registry metrics.Registry) (grpc.UnaryClientInterceptor, error) {
+
+	duration, err := clientDuration(registry)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		var start = time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		observe(duration, method, start, err)
+		return err
+	}, nil
+}
+
+// StreamClientMetrics interceptor record per-method stream creation time histogram.
+func StreamClientMetrics(registry metrics.Registry) (grpc.StreamClientInterceptor, error) {
+
+	duration, err := clientDuration(registry)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		var start = time.Now()
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		observe(duration, method, start, err)
+		return cs, err
+	}, nil
+}
+
+// serverDuration register server handling time metric.
+func serverDuration(registry metrics.Registry) (metrics.Duration, error) {
+	return metrics.NewDuration(registry,
+		"grpc_server_handling_seconds",
+		"Duration of grpc server calls in seconds.",
+		metricsLabels...,
+	)
+}
+
+// clientDuration register client handling time metric.
+func clientDuration(registry metrics.Registry) (metrics.Duration, error) {
+	return metrics.NewDuration(registry,
+		"grpc_client_handling_seconds",
+		"Duration of grpc client calls in seconds.",
+		metricsLabels...,
+	)
+}
+
+// observe call duration.
+func observe(duration metrics.Duration, fullMethod string, start time.Time, err error) {
+	var service, method = splitMethod(fullMethod)
+	duration.WithLabelValues(service, method, status.Code(err).String()).Observe(time.Since(start).Seconds())
+}
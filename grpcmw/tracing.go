@@ -0,0 +1,133 @@
+package grpcmw
+
+import (
+	"context"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/tarusov/rig/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// componentName is opentracing component tag value.
+const componentName = "gRPC"
+
+// UnaryServerTracing interceptor start server span, parent span is extracted from metadata.
+// If tracer is nil, global tracer will be used.
+func UnaryServerTracing(tracer opentracing.Tracer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+
+		var span = startServerSpan(ctx, tracer, info.FullMethod)
+		defer span.Finish()
+
+		resp, err := handler(opentracing.ContextWithSpan(ctx, span), req)
+		finishSpan(span, err)
+
+		return resp, err
+	}
+}
+
+// StreamServerTracing interceptor start server span, parent span is extracted from metadata.
+// If tracer is nil, global tracer will be used.
+func StreamServerTracing(tracer opentracing.Tracer) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		var span = startServerSpan(ss.Context(), tracer, info.FullMethod)
+		defer span.Finish()
+
+		err := handler(srv, wrapServerStream(ss, opentracing.ContextWithSpan(ss.Context(), span)))
+		finishSpan(span, err)
+
+		return err
+	}
+}
+
+// UnaryClientTracing interceptor start client span and inject it into outgoing metadata.
+// If tracer is nil, global tracer will be used.
+func UnaryClientTracing(tracer opentracing.Tracer) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+
+		var span, spanCtx = startClientSpan(ctx, tracer, method)
+		defer span.Finish()
+
+		err := invoker(spanCtx, method, req, reply, cc, opts...)
+		finishSpan(span, err)
+
+		return err
+	}
+}
+
+// StreamClientTracing interceptor start client span and inject it into outgoing metadata.
+// Span is finished when stream is created. If tracer is nil, global tracer will be used.
+func StreamClientTracing(tracer opentracing.Tracer) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+
+		var span, spanCtx = startClientSpan(ctx, tracer, method)
+		defer span.Finish()
+
+		cs, err := streamer(spanCtx, desc, cc, method, opts...)
+		finishSpan(span, err)
+
+		return cs, err
+	}
+}
+
+// startServerSpan extract parent span from incoming metadata and start new server span.
+func startServerSpan(ctx context.Context, tracer opentracing.Tracer, fullMethod string) opentracing.Span {
+
+	if tracer == nil {
+		tracer = opentracing.GlobalTracer()
+	}
+
+	var md, _ = metadata.FromIncomingContext(ctx)
+	wireCtx, err := tracer.Extract(opentracing.TextMap, metadataCarrier(md.Copy()))
+	if err != nil && err != opentracing.ErrSpanContextNotFound {
+		logger.FromContext(ctx).WithErr(err).Warn("failed to extract span context")
+	}
+
+	var span = tracer.StartSpan(fullMethod, ext.RPCServerOption(wireCtx))
+	ext.Component.Set(span, componentName)
+
+	return span
+}
+
+// startClientSpan start client span and inject it into outgoing metadata.
+func startClientSpan(ctx context.Context, tracer opentracing.Tracer, fullMethod string) (opentracing.Span, context.Context) {
+
+	if tracer == nil {
+		tracer = opentracing.GlobalTracer()
+	}
+
+	var opts = []opentracing.StartSpanOption{ext.SpanKindRPCClient}
+	if parent := opentracing.SpanFromContext(ctx); parent != nil {
+		opts = append(opts, opentracing.ChildOf(parent.Context()))
+	}
+
+	var span = tracer.StartSpan(fullMethod, opts...)
+	ext.Component.Set(span, componentName)
+
+	var md, ok = metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.New(nil)
+	}
+
+	if err := tracer.Inject(span.Context(), opentracing.TextMap, metadataCarrier(md)); err != nil {
+		logger.FromContext(ctx).WithErr(err).Warn("failed to inject span context")
+	}
+
+	return span, opentracing.ContextWithSpan(metadata.NewOutgoingContext(ctx, md), span)
+}
+
+// finishSpan set call result tags.
+func finishSpan(span opentracing.Span, err error) {
+	var code = status.Code(err)
+	span.SetTag("grpc.code", code.String())
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.SetTag("error.message", err.Error())
+	}
+}
@@ -0,0 +1,66 @@
+package grpcmw
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/tarusov/rig/validator"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerValidation interceptor convert validator.FieldErrors returned
+// by handler into InvalidArgument status with BadRequest details.
+func UnaryServerValidation() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, validationError(err)
+	}
+}
+
+// StreamServerValidation interceptor convert validator.FieldErrors returned
+// by handler into InvalidArgument status with BadRequest details.
+func StreamServerValidation() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return validationError(handler(srv, ss))
+	}
+}
+
+// ValidationStatus create InvalidArgument status with field violations details.
+func ValidationStatus(fieldErrors validator.FieldErrors) *status.Status {
+
+	var fields = make([]string, 0, len(fieldErrors))
+	for field := range fieldErrors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var br = &errdetails.BadRequest{
+		FieldViolations: make([]*errdetails.BadRequest_FieldViolation, 0, len(fields)),
+	}
+	for _, field := range fields {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: fieldErrors[field],
+		})
+	}
+
+	var st = status.New(codes.InvalidArgument, "validation failed")
+	if detailed, err := st.WithDetails(br); err == nil {
+		return detailed
+	}
+
+	return st
+}
+
+// validationError convert field errors into grpc status error.
+func validationError(err error) error {
+	var fieldErrors validator.FieldErrors
+	if errors.As(err, &fieldErrors) {
+		return ValidationStatus(fieldErrors).Err()
+	}
+	return err
+}
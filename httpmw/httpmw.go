@@ -0,0 +1,41 @@
+// Package httpmw contains chi compatible http middlewares.
+package httpmw
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Middleware is http handler modificator.
+type Middleware func(http.Handler) http.Handler
+
+// unmatchedRoute is route label for requests without chi route pattern.
+const unmatchedRoute = "unmatched"
+
+// routePattern return chi route pattern for request.
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+	return unmatchedRoute
+}
+
+// wrapWriter wrap response writer for status and size capture.
+func wrapWriter(w http.ResponseWriter, r *http.Request) middleware.WrapResponseWriter {
+	if ww, ok := w.(middleware.WrapResponseWriter); ok {
+		return ww
+	}
+	return middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+}
+
+// statusCode return response status, 200 if header was not written explicitly.
+func statusCode(ww middleware.WrapResponseWriter) int {
+	if status := ww.Status(); status != 0 {
+		return status
+	}
+	return http.StatusOK
+}
@@ -0,0 +1,140 @@
+package httpmw_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/httpmw"
+	"github.com/tarusov/rig/logger"
+	"github.com/tarusov/rig/metrics"
+)
+
+func TestRequestID(t *testing.T) {
+
+	var requestID string
+	var h = httpmw.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = httpmw.RequestIDFromContext(r.Context())
+	}))
+
+	var rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NotEmptyf(t, requestID, "TestRequestID: request id not generated")
+	require.Equalf(t, requestID, rec.Header().Get(httpmw.HeaderRequestID), "TestRequestID: response header mismatch")
+
+	var req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(httpmw.HeaderRequestID, "external-id")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	require.Equalf(t, "external-id", requestID, "TestRequestID: incoming request id not used")
+}
+
+func TestLoggerAndRecoverer(t *testing.T) {
+
+	var (
+		buf = bytes.NewBuffer(make([]byte, 0))
+		log = logger.New(logger.WithLoggingOutput(buf))
+		mux = chi.NewMux()
+	)
+
+	mux.Use(httpmw.RequestID, httpmw.Logger(log), httpmw.Recoverer(nil))
+	mux.Get("/panic", func(w http.ResponseWriter, r *http.Request) {
+		logger.FromContext(r.Context()).Info("handler called")
+		panic("boom")
+	})
+
+	var req = httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set(httpmw.HeaderRequestID, "panic-request")
+
+	var rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	require.Equalf(t, http.StatusInternalServerError, rec.Code, "TestLoggerAndRecoverer: unexpected status code")
+	require.Containsf(t, buf.String(), "handler called", "TestLoggerAndRecoverer: context logger not used")
+	require.Containsf(t, buf.String(), "panic-request", "TestLoggerAndRecoverer: request id not logged")
+	require.Containsf(t, buf.String(), "boom", "TestLoggerAndRecoverer: panic not logged")
+}
+
+func TestTracing(t *testing.T) {
+
+	var (
+		tracer = mocktracer.New()
+		mux    = chi.NewMux()
+	)
+
+	mux.Use(httpmw.Tracing(tracer))
+	mux.Get("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/1", nil))
+
+	var spans = tracer.FinishedSpans()
+	require.Lenf(t, spans, 1, "TestTracing: unexpected spans count")
+	require.Equalf(t, "HTTP GET /items/{id}", spans[0].OperationName, "TestTracing: unexpected operation name")
+	require.Equalf(t, uint16(http.StatusNotFound), spans[0].Tag("http.status_code"), "TestTracing: unexpected status tag")
+}
+
+func TestMetrics(t *testing.T) {
+
+	var (
+		registry = metrics.New()
+		mux      = chi.NewMux()
+	)
+
+	mw, err := httpmw.Metrics(registry)
+	require.ErrorIsf(t, err, nil, "TestMetrics: unexpected middleware error: %v", err)
+
+	// Second registration must reuse existing collectors.
+	_, err = httpmw.Metrics(registry)
+	require.ErrorIsf(t, err, nil, "TestMetrics: unexpected middleware error: %v", err)
+
+	mux.Use(mw)
+	mux.Get("/items/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/1", nil))
+
+	families, err := registry.Gather()
+	require.ErrorIsf(t, err, nil, "TestMetrics: unexpected gather error: %v", err)
+
+	var found bool
+	for _, mf := range families {
+		if mf.GetName() != "http_requests_total" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			for _, lp := range m.GetLabel() {
+				if lp.GetName() == "route" && lp.GetValue() == "/items/{id}" {
+					found = true
+				}
+			}
+		}
+	}
+	require.Truef(t, found, "TestMetrics: request metric not recorded")
+}
+
+// countingWriter count WriteHeader calls.
+type countingWriter struct {
+	*httptest.ResponseRecorder
+	calls int
+}
+
+func (w *countingWriter) WriteHeader(code int) {
+	w.calls++
+	w.ResponseRecorder.WriteHeader(code)
+}
+
+func TestRecovererHeaderWritten(t *testing.T) {
+
+	var h = httpmw.Recoverer(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		panic("boom")
+	}))
+
+	var w = &countingWriter{ResponseRecorder: httptest.NewRecorder()}
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equalf(t, http.StatusAccepted, w.Code, "TestRecovererHeaderWritten: written status overwritten")
+	require.Equalf(t, 1, w.calls, "TestRecovererHeaderWritten: superfluous WriteHeader call")
+}
@@ -0,0 +1,48 @@
+package httpmw
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/tarusov/rig/logger"
+)
+
+// Logger middleware put request scoped logger into context and log
+// completed requests. Request id is added to logger fields if present.
+func Logger(log *logger.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			var (
+				start  = time.Now()
+				ww     = wrapWriter(w, r)
+				reqLog = log.WithFields(logger.Fields{
+					"method": r.Method,
+					"path":   r.URL.Path,
+				})
+			)
+
+			if requestID := RequestIDFromContext(r.Context()); requestID != "" {
+				reqLog = reqLog.WithField(logger.FieldNameRequestID, requestID)
+			}
+
+			next.ServeHTTP(ww, r.WithContext(logger.ContextWithLogger(r.Context(), reqLog)))
+
+			var (
+				status = statusCode(ww)
+				event  = reqLog.WithFields(logger.Fields{
+					"status":   status,
+					"bytes":    ww.BytesWritten(),
+					"duration": time.Since(start),
+				})
+			)
+
+			if status >= http.StatusInternalServerError {
+				event.Error("request completed")
+				return
+			}
+
+			event.Info("request completed")
+		})
+	}
+}
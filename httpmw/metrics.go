@@ -0,0 +1,52 @@
+package httpmw
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tarusov/rig/metrics"
+)
+
+// Metrics middleware record RED metrics (rate, errors, duration) for each
+// request labeled by method, chi route pattern and response status code.
+func Metrics(registry metrics.Registry) (Middleware, error) {
+
+	requests, err := metrics.NewCount(registry,
+		"http_requests_total",
+		"Total number of http requests.",
+		"method", "route", "code",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	duration, err := metrics.NewDuration(registry,
+		"http_request_duration_seconds",
+		"Duration of http requests in seconds.",
+		"method", "route", "code",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			var (
+				start = time.Now()
+				ww    = wrapWriter(w, r)
+			)
+
+			next.ServeHTTP(ww, r)
+
+			var (
+				route = routePattern(r)
+				code  = strconv.Itoa(statusCode(ww))
+			)
+
+			requests.WithLabelValues(r.Method, route, code).Inc()
+			duration.WithLabelValues(r.Method, route, code).Observe(time.Since(start).Seconds())
+		})
+	}, nil
+}
@@ -0,0 +1,47 @@
+package httpmw
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"github.com/tarusov/rig/logger"
+)
+
+// Recoverer middleware recover handler panics into 500 response. Panic is
+// logged with context logger and reported to sentry if notifier is not nil.
+// If handler already wrote response header, response is left as is.
+func Recoverer(notifier *logger.SentryNotifier) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			var ww = wrapWriter(w, r)
+
+			defer func() {
+				var rvr = recover()
+				if rvr == nil {
+					return
+				}
+
+				// Aborted handler must not be recovered, see http.ErrAbortHandler.
+				if rvr == http.ErrAbortHandler {
+					panic(rvr)
+				}
+
+				logger.FromContext(r.Context()).WithFields(logger.Fields{
+					"panic": rvr,
+					"stack": string(debug.Stack()),
+				}).Error("http handler panic recovered")
+
+				if notifier != nil {
+					notifier.Recover(rvr)
+				}
+
+				if ww.Status() == 0 {
+					ww.WriteHeader(http.StatusInternalServerError)
+				}
+			}()
+
+			next.ServeHTTP(ww, r)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package httpmw
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// HeaderRequestID is request id header name.
+const HeaderRequestID = "X-Request-Id"
+
+// requestIDContextKey is custom context key for request id.
+type requestIDContextKey struct{}
+
+// RequestIDFromContext extract request id from context or return empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx != nil {
+		if v, ok := ctx.Value(requestIDContextKey{}).(string); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// ContextWithRequestID insert request id into context.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestID middleware take request id from header or generate new one,
+// put it into request context and response header.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		var requestID = r.Header.Get(HeaderRequestID)
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+
+		w.Header().Set(HeaderRequestID, requestID)
+		next.ServeHTTP(w, r.WithContext(ContextWithRequestID(r.Context(), requestID)))
+	})
+}
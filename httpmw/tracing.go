@@ -0,0 +1,49 @@
+package httpmw
+
+import (
+	"net/http"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/tarusov/rig/logger"
+)
+
+// Tracing middleware start opentracing server span for each request.
+// Parent span is extracted from request headers. If tracer is nil,
+// global tracer will be used.
+func Tracing(tracer opentracing.Tracer) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			var t = tracer
+			if t == nil {
+				t = opentracing.GlobalTracer()
+			}
+
+			wireCtx, err := t.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(r.Header))
+			if err != nil && err != opentracing.ErrSpanContextNotFound {
+				logger.FromContext(r.Context()).WithErr(err).Warn("failed to extract span context")
+			}
+
+			var span = t.StartSpan("HTTP "+r.Method, ext.RPCServerOption(wireCtx))
+			defer span.Finish()
+
+			ext.Component.Set(span, "net/http")
+			ext.HTTPMethod.Set(span, r.Method)
+			ext.HTTPUrl.Set(span, r.URL.String())
+			if requestID := RequestIDFromContext(r.Context()); requestID != "" {
+				span.SetTag(logger.FieldNameRequestID, requestID)
+			}
+
+			var ww = wrapWriter(w, r)
+			next.ServeHTTP(ww, r.WithContext(opentracing.ContextWithSpan(r.Context(), span)))
+
+			var status = statusCode(ww)
+			span.SetOperationName("HTTP " + r.Method + " " + routePattern(r))
+			ext.HTTPStatusCode.Set(span, uint16(status))
+			if status >= http.StatusInternalServerError {
+				ext.Error.Set(span, true)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Package testcontainer contains shared test servers setup for TestMain.
+package testcontainer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// TerminateFunc stop test server. It must be called before os.Exit, because
+// deferred calls are not run on exit.
+type TerminateFunc func()
+
+// Redis return uri of redis server for tests. External server may be set with
+// REDIS_URI env, otherwise redis container is started.
+func Redis(ctx context.Context) (string, TerminateFunc, error) {
+
+	if uri := os.Getenv("REDIS_URI"); uri != "" {
+		return uri, func() {}, nil
+	}
+
+	req := testcontainers.ContainerRequest{
+		Image:        "redis:6",
+		ExposedPorts: []string{"6379/tcp"},
+		WaitingFor:   wait.ForLog("* Ready to accept connections"),
+	}
+
+	container, addr, err := start(ctx, req, "6379")
+	if err != nil {
+		return "", func() {}, err
+	}
+
+	return "redis://" + addr, terminate(ctx, container), nil
+}
+
+// Postgres return uri of postgres server for tests. External server may be
+// set with POSTGRES_URI env, otherwise postgres container is started.
+func Postgres(ctx context.Context) (string, TerminateFunc, error) {
+
+	if uri := os.Getenv("POSTGRES_URI"); uri != "" {
+		return uri, func() {}, nil
+	}
+
+	req := testcontainers.ContainerRequest{
+		Image:        "postgres:13",
+		ExposedPorts: []string{"5432/tcp"},
+		Env: map[string]string{
+			"POSTGRES_USER":     "test",
+			"POSTGRES_PASSWORD": "test",
+			"POSTGRES_DB":       "test",
+		},
+		WaitingFor: wait.ForLog("database system is ready to accept connections").WithOccurrence(2),
+	}
+
+	container, addr, err := start(ctx, req, "5432")
+	if err != nil {
+		return "", func() {}, err
+	}
+
+	return fmt.Sprintf("postgres://test:test@%s/test?sslmode=disable", addr), terminate(ctx, container), nil
+}
+
+// start run container and return its host:port address.
+func start(ctx context.Context, req testcontainers.ContainerRequest, port nat.Port) (testcontainers.Container, string, error) {
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	mappedPort, err := container.MappedPort(ctx, port)
+	if err != nil {
+		terminate(ctx, container)()
+		return nil, "", err
+	}
+
+	hostIP, err := container.Host(ctx)
+	if err != nil {
+		terminate(ctx, container)()
+		return nil, "", err
+	}
+
+	return container, hostIP + ":" + mappedPort.Port(), nil
+}
+
+// terminate return container stop func.
+func terminate(ctx context.Context, container testcontainers.Container) TerminateFunc {
+	return func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Println("failed to terminate test container", err)
+		}
+	}
+}
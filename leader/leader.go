@@ -0,0 +1,323 @@
+// Package leader contains redis lock based leader election.
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tarusov/rig/locker"
+	"github.com/tarusov/rig/logger"
+	"github.com/tarusov/rig/metrics"
+)
+
+type (
+	// Elector struct.
+	Elector struct {
+		mu     sync.Mutex
+		lock   locker.Lock
+		ctx    context.Context
+		cancel context.CancelFunc
+		subs   []LostFunc
+
+		locker        Locker
+		key           string
+		ttl           time.Duration
+		retryInterval time.Duration
+
+		state metrics.Gauge
+	}
+
+	// RunFunc is func which runs only while instance is leader.
+	// Context is cancelled when leadership is lost.
+	RunFunc func(ctx context.Context) error
+
+	// LostFunc is leadership lost callback.
+	LostFunc func(key string)
+
+	// Locker is leadership lock driver, it is implemented by locker package.
+	Locker interface {
+		// Obtain obtain mutex lock of key. Return lock handle or error.
+		Obtain(ctx context.Context, key string, ttl time.Duration) (locker.Lock, error)
+	}
+)
+
+// Defaults.
+const (
+	defaultTTL           = 15 * time.Second
+	defaultRetryInterval = time.Second
+)
+
+// New creates new leader elector instance. All instances with same key
+// take part in the same election.
+func New(l Locker, key string, opts ...electorOption) (*Elector, error) {
+
+	var eo = &electorOptions{
+		ttl:           defaultTTL,
+		retryInterval: defaultRetryInterval,
+	}
+
+	for _, opt := range opts {
+		opt(eo)
+	}
+
+	if l == nil {
+		return nil, errors.New("locker is not defined")
+	}
+	if key == "" {
+		return nil, errors.New("election key is empty")
+	}
+	if eo.ttl < time.Millisecond {
+		return nil, fmt.Errorf("invalid leadership ttl: %v", eo.ttl)
+	}
+	if eo.retryInterval <= 0 {
+		return nil, fmt.Errorf("invalid retry interval: %v", eo.retryInterval)
+	}
+
+	var e = &Elector{
+		locker:        l,
+		key:           key,
+		ttl:           eo.ttl,
+		retryInterval: eo.retryInterval,
+	}
+
+	if eo.registry != nil {
+		var err error
+
+		e.state, err = metrics.NewGauge(eo.registry,
+			"leader_state",
+			"Leadership state, 1 if instance is leader.",
+			"key",
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		e.state.WithLabelValues(key).Set(0)
+	}
+
+	return e, nil
+}
+
+// Key return election key.
+func (e *Elector) Key() string {
+	return e.key
+}
+
+// IsLeader return true if instance is current leader.
+func (e *Elector) IsLeader() bool {
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.lock != nil
+}
+
+// OnLost add leadership lost callback. Callbacks are not called on Resign.
+func (e *Elector) OnLost(fn LostFunc) {
+
+	e.mu.Lock()
+	e.subs = append(e.subs, fn)
+	e.mu.Unlock()
+}
+
+// Campaign blocks until instance become leader or ctx is done. Returned
+// context is cancelled when leadership is lost or resigned. Leadership lock
+// is refreshed in background until that.
+func (e *Elector) Campaign(ctx context.Context) (context.Context, error) {
+
+	var log = logger.FromContext(ctx).WithField("key", e.key)
+
+	for {
+		e.mu.Lock()
+		if e.lock != nil {
+			var lCtx = e.ctx
+			e.mu.Unlock()
+			return lCtx, nil
+		}
+		e.mu.Unlock()
+
+		lock, err := e.locker.Obtain(ctx, e.key, e.ttl)
+		if err == nil {
+			log.Info("leadership acquired")
+			return e.elect(ctx, lock), nil
+		}
+		if !errors.Is(err, locker.ErrLockNotObtained) && ctx.Err() == nil {
+			log.WithErr(err).Warn("failed to campaign")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(e.retryInterval):
+		}
+	}
+}
+
+// Resign release leadership if instance is leader.
+func (e *Elector) Resign(ctx context.Context) error {
+
+	e.mu.Lock()
+	var lock = e.lock
+	e.mu.Unlock()
+
+	if lock == nil {
+		return nil
+	}
+
+	return e.resign(ctx, lock)
+}
+
+// Run campaign and call fn while instance is leader. If leadership is lost,
+// fn context is cancelled and campaign is started again. Run returns when ctx
+// is done or fn returns error, leadership is resigned before return.
+func (e *Elector) Run(ctx context.Context, fn RunFunc) error {
+
+	for {
+		lCtx, err := e.Campaign(ctx)
+		if err != nil {
+			return nil
+		}
+
+		err = fn(lCtx)
+
+		rCtx, rCancel := context.WithTimeout(context.Background(), e.ttl)
+		if rErr := e.Resign(rCtx); rErr != nil {
+			logger.FromContext(ctx).WithField("key", e.key).WithErr(rErr).Warn("failed to resign")
+		}
+		rCancel()
+
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(e.retryInterval):
+		}
+	}
+}
+
+// elect save obtained lock as leadership and start refreshing it.
+func (e *Elector) elect(ctx context.Context, lock locker.Lock) context.Context {
+
+	var lCtx, lCancel = context.WithCancel(ctx)
+
+	e.mu.Lock()
+	e.lock, e.ctx, e.cancel = lock, lCtx, lCancel
+	e.mu.Unlock()
+
+	e.setState(1)
+
+	go e.refresh(ctx, lCtx, lock)
+
+	return lCtx
+}
+
+// reset clear leadership if given lock is current. Return false otherwise.
+func (e *Elector) reset(lock locker.Lock) bool {
+
+	e.mu.Lock()
+	if e.lock != lock {
+		e.mu.Unlock()
+		return false
+	}
+	var cancel = e.cancel
+	e.lock, e.ctx, e.cancel = nil, nil, nil
+	e.mu.Unlock()
+
+	cancel()
+	e.setState(0)
+
+	return true
+}
+
+// resign clear leadership and release lock.
+func (e *Elector) resign(ctx context.Context, lock locker.Lock) error {
+
+	if !e.reset(lock) {
+		return nil
+	}
+
+	logger.FromContext(ctx).WithField("key", e.key).Info("leadership resigned")
+
+	err := lock.Release(ctx)
+	if errors.Is(err, locker.ErrNotLocked) {
+		return nil
+	}
+
+	return err
+}
+
+// lost clear leadership and notify subscribers.
+func (e *Elector) lost(ctx context.Context, lock locker.Lock) {
+
+	if !e.reset(lock) {
+		return
+	}
+
+	logger.FromContext(ctx).WithField("key", e.key).Warn("leadership lost")
+
+	e.mu.Lock()
+	var subs = append([]LostFunc(nil), e.subs...)
+	e.mu.Unlock()
+
+	for _, fn := range subs {
+		fn(e.key)
+	}
+}
+
+// refresh extend leadership lock every ttl/3 until leadership ends. It is
+// lost if lock is taken by other holder or not refreshed during ttl. If ctx
+// is done, leadership is resigned.
+func (e *Elector) refresh(ctx, lCtx context.Context, lock locker.Lock) {
+
+	var (
+		ticker   = time.NewTicker(e.ttl / 3)
+		deadline = time.Now().Add(e.ttl)
+		log      = logger.FromContext(ctx).WithField("key", e.key)
+	)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-lCtx.Done():
+			if ctx.Err() != nil {
+				rCtx, rCancel := context.WithTimeout(context.Background(), e.ttl)
+				if err := e.resign(rCtx, lock); err != nil {
+					log.WithErr(err).Warn("failed to resign")
+				}
+				rCancel()
+			}
+			return
+		case <-ticker.C:
+		}
+
+		err := lock.Refresh(lCtx, e.ttl)
+		if err == nil {
+			deadline = time.Now().Add(e.ttl)
+			continue
+		}
+		if lCtx.Err() != nil {
+			continue
+		}
+
+		if errors.Is(err, locker.ErrNotLocked) || time.Now().After(deadline) {
+			log.WithErr(err).Warn("failed to refresh leadership")
+			e.lost(ctx, lock)
+			return
+		}
+
+		log.WithErr(err).Warn("failed to refresh leadership lock")
+	}
+}
+
+// setState update leadership state metric.
+func (e *Elector) setState(v float64) {
+	if e.state != nil {
+		e.state.WithLabelValues(e.key).Set(v)
+	}
+}
@@ -0,0 +1,41 @@
+package leader
+
+import (
+	"time"
+
+	"github.com/tarusov/rig/metrics"
+)
+
+type (
+	// electorOption is elector constructor optional modificator.
+	electorOption func(*electorOptions)
+
+	// electorOptions is auxilary constructor struct.
+	electorOptions struct {
+		ttl           time.Duration
+		retryInterval time.Duration
+		registry      metrics.Registry
+	}
+)
+
+// WithTTL setup leadership lock ttl. Lock is refreshed every ttl/3, so
+// leadership of crashed instance is taken over in ttl at most.
+func WithTTL(ttl time.Duration) electorOption {
+	return func(eo *electorOptions) {
+		eo.ttl = ttl
+	}
+}
+
+// WithRetryInterval setup delay between campaign attempts.
+func WithRetryInterval(interval time.Duration) electorOption {
+	return func(eo *electorOptions) {
+		eo.retryInterval = interval
+	}
+}
+
+// WithMetrics setup registry for leadership state metric.
+func WithMetrics(registry metrics.Registry) electorOption {
+	return func(eo *electorOptions) {
+		eo.registry = registry
+	}
+}
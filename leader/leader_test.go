@@ -0,0 +1,167 @@
+package leader_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/internal/testcontainer"
+	"github.com/tarusov/rig/leader"
+	"github.com/tarusov/rig/locker"
+	"github.com/tarusov/rig/metrics"
+)
+
+// redisURI for tests. External server may be set with REDIS_URI env.
+var redisURI string
+
+func TestMain(m *testing.M) {
+
+	uri, terminate, err := testcontainer.Redis(context.Background())
+	if err != nil {
+		log.Println("redis container not inited", err)
+	}
+	redisURI = uri
+
+	var code = m.Run()
+	terminate()
+	os.Exit(code)
+}
+
+func newLocker(t *testing.T) (leader.Locker, redis.UniversalClient) {
+
+	if redisURI == "" {
+		t.Skip("server not inited")
+	}
+
+	opts, err := redis.ParseURL(redisURI)
+	require.ErrorIsf(t, err, nil, "%s: unexpected parse uri: %v", t.Name(), err)
+
+	var client = redis.NewClient(opts)
+	t.Cleanup(func() { _ = client.Close() })
+
+	l, err := locker.New(client, locker.WithRetryCount(0))
+	require.ErrorIsf(t, err, nil, "%s: unexpected locker error: %v", t.Name(), err)
+
+	return l, client
+}
+
+func TestElectorCampaign(t *testing.T) {
+
+	l, _ := newLocker(t)
+
+	var key = uuid.NewString()
+
+	first, err := leader.New(l, key, leader.WithTTL(time.Second), leader.WithMetrics(metrics.New()))
+	require.ErrorIsf(t, err, nil, "TestElectorCampaign: unexpected elector error: %v", err)
+
+	second, err := leader.New(l, key, leader.WithTTL(time.Second), leader.WithRetryInterval(50*time.Millisecond))
+	require.ErrorIsf(t, err, nil, "TestElectorCampaign: unexpected elector error: %v", err)
+
+	lCtx, err := first.Campaign(context.Background())
+	require.ErrorIsf(t, err, nil, "TestElectorCampaign: unexpected campaign error: %v", err)
+	require.Truef(t, first.IsLeader(), "TestElectorCampaign: first is not leader")
+
+	// Leadership is kept longer than ttl by refresh.
+	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+
+	_, err = second.Campaign(ctx)
+	require.ErrorIsf(t, err, context.DeadlineExceeded, "TestElectorCampaign: unexpected campaign error: %v", err)
+	require.Falsef(t, second.IsLeader(), "TestElectorCampaign: second is leader")
+
+	err = first.Resign(context.Background())
+	require.ErrorIsf(t, err, nil, "TestElectorCampaign: unexpected resign error: %v", err)
+	require.Falsef(t, first.IsLeader(), "TestElectorCampaign: first is leader after resign")
+	require.ErrorIsf(t, lCtx.Err(), context.Canceled, "TestElectorCampaign: leadership context is not cancelled")
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err = second.Campaign(ctx)
+	require.ErrorIsf(t, err, nil, "TestElectorCampaign: unexpected campaign error: %v", err)
+	require.Truef(t, second.IsLeader(), "TestElectorCampaign: second is not leader")
+
+	err = second.Resign(context.Background())
+	require.ErrorIsf(t, err, nil, "TestElectorCampaign: unexpected resign error: %v", err)
+}
+
+func TestElectorLost(t *testing.T) {
+
+	l, client := newLocker(t)
+
+	var key = uuid.NewString()
+
+	e, err := leader.New(l, key, leader.WithTTL(300*time.Millisecond))
+	require.ErrorIsf(t, err, nil, "TestElectorLost: unexpected elector error: %v", err)
+
+	var lost = make(chan string, 1)
+	e.OnLost(func(key string) {
+		lost <- key
+	})
+
+	lCtx, err := e.Campaign(context.Background())
+	require.ErrorIsf(t, err, nil, "TestElectorLost: unexpected campaign error: %v", err)
+
+	// Simulate lock takeover.
+	err = client.Set(context.Background(), key, "other", time.Minute).Err()
+	require.ErrorIsf(t, err, nil, "TestElectorLost: unexpected redis error: %v", err)
+
+	select {
+	case got := <-lost:
+		require.Equalf(t, key, got, "TestElectorLost: unexpected lost key")
+	case <-time.After(time.Second):
+		t.Fatal("TestElectorLost: leadership lost is not detected")
+	}
+
+	require.ErrorIsf(t, lCtx.Err(), context.Canceled, "TestElectorLost: leadership context is not cancelled")
+	require.Falsef(t, e.IsLeader(), "TestElectorLost: elector is leader after lost")
+}
+
+func TestElectorRun(t *testing.T) {
+
+	l, _ := newLocker(t)
+
+	var key = uuid.NewString()
+
+	e, err := leader.New(l, key, leader.WithTTL(time.Minute))
+	require.ErrorIsf(t, err, nil, "TestElectorRun: unexpected elector error: %v", err)
+
+	var (
+		started   = make(chan struct{})
+		ctx, stop = context.WithCancel(context.Background())
+		done      = make(chan error, 1)
+	)
+
+	go func() {
+		done <- e.Run(ctx, func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return nil
+		})
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("TestElectorRun: leader func is not started")
+	}
+
+	stop()
+
+	select {
+	case err = <-done:
+		require.ErrorIsf(t, err, nil, "TestElectorRun: unexpected run error: %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("TestElectorRun: run is not stopped")
+	}
+
+	// Lock is released on shutdown, so it is obtained immediately.
+	lock, err := l.Obtain(context.Background(), key, time.Second)
+	require.ErrorIsf(t, err, nil, "TestElectorRun: leadership is not resigned: %v", err)
+	_ = lock.Release(context.Background())
+}
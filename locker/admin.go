@@ -0,0 +1,100 @@
+package locker
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/tarusov/rig/logger"
+)
+
+type (
+	// adminLock is admin list response item.
+	adminLock struct {
+		Key   string   `json:"key"`
+		Kind  LockKind `json:"kind"`
+		TTL   string   `json:"ttl"`
+		Owner Owner    `json:"owner"`
+	}
+
+	// adminRelease is admin force release response.
+	adminRelease struct {
+		Released int `json:"released"`
+	}
+)
+
+// AdminHandler return locks admin handler. GET lists held locks by required
+// "prefix" query param, DELETE force releases lock by "key" and optional
+// "owner" id query params. Force release is forbidden unless WithForceRelease
+// is set. Handler is not mounted anywhere by default, it should be added to
+// admin router explicitly.
+func AdminHandler(l *RedisLocker, opts ...adminOption) http.Handler {
+
+	var ao = &adminOptions{}
+	for _, opt := range opts {
+		opt(ao)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		var query = r.URL.Query()
+
+		switch r.Method {
+		case http.MethodGet:
+			var prefix = query.Get("prefix")
+			if prefix == "" {
+				http.Error(w, "prefix is required", http.StatusBadRequest)
+				return
+			}
+
+			infos, err := l.List(r.Context(), prefix)
+			if err != nil {
+				logger.FromContext(r.Context()).WithErr(err).Error("failed to list locks")
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			var resp = make([]adminLock, 0, len(infos))
+			for _, info := range infos {
+				resp = append(resp, adminLock{
+					Key:   info.Key,
+					Kind:  info.Kind,
+					TTL:   info.TTL.String(),
+					Owner: info.Owner,
+				})
+			}
+			writeJSON(w, r, resp)
+
+		case http.MethodDelete:
+			if !ao.forceRelease {
+				http.Error(w, "force release is disabled", http.StatusForbidden)
+				return
+			}
+
+			var key = query.Get("key")
+			if key == "" {
+				http.Error(w, "key is required", http.StatusBadRequest)
+				return
+			}
+
+			released, err := l.ForceRelease(r.Context(), key, query.Get("owner"))
+			if err != nil {
+				logger.FromContext(r.Context()).WithErr(err).Error("failed to force release lock")
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			writeJSON(w, r, adminRelease{Released: released})
+
+		default:
+			w.Header().Set("Allow", "GET, DELETE")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// writeJSON write json response.
+func writeJSON(w http.ResponseWriter, r *http.Request, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.FromContext(r.Context()).WithErr(err).Error("failed to write admin response")
+	}
+}
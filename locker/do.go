@@ -0,0 +1,43 @@
+package locker
+
+import (
+	"context"
+	"time"
+)
+
+// DoFunc is func, which runs while lock is held. Context is cancelled if
+// lock is lost.
+type DoFunc func(ctx context.Context) error
+
+// Do obtain lock of key, run fn and release lock. Lock is auto refreshed
+// while fn runs and released on fn panic too. Return fn error, or release
+// error (ErrNotLocked if lock was lost during run).
+func Do(ctx context.Context, l Locker, key string, ttl time.Duration, fn DoFunc) error {
+
+	lock, err := l.Obtain(ctx, key, ttl)
+	if err != nil {
+		return err
+	}
+
+	return run(ctx, lock, ttl, fn)
+}
+
+// run call fn with auto refreshed lock and release lock after it.
+func run(ctx context.Context, lock Lock, ttl time.Duration, fn DoFunc) (err error) {
+
+	var rCtx, rCancel = AutoRefresh(ctx, lock, ttl)
+
+	defer func() {
+		rCancel()
+
+		// Lock is released even if ctx is done.
+		var uCtx, uCancel = context.WithTimeout(context.Background(), ttl)
+		defer uCancel()
+
+		if uErr := lock.Release(uCtx); err == nil {
+			err = uErr
+		}
+	}()
+
+	return fn(rCtx)
+}
@@ -0,0 +1,204 @@
+package locker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/tarusov/rig/metrics"
+)
+
+type (
+	// instrumented is locker wrapper, which records metrics and traces.
+	instrumented struct {
+		Locker
+		tracer opentracing.Tracer
+		prefix func(key string) string
+
+		obtains metrics.Count
+		wait    metrics.Duration
+		hold    metrics.Duration
+	}
+
+	// instrumentedLock is lock handle wrapper, which records hold time.
+	instrumentedLock struct {
+		Lock
+		i        *instrumented
+		obtained time.Time
+		once     sync.Once
+	}
+)
+
+// Obtain results.
+const (
+	resultObtained    = "obtained"
+	resultNotObtained = "not_obtained"
+	resultError       = "error"
+)
+
+// componentName is opentracing component tag value.
+const componentName = "locker"
+
+// defaultKeyPrefix is metrics label of keys without prefix.
+const defaultKeyPrefix = "default"
+
+// Instrument wrap locker to record obtain results, wait and hold time metrics
+// labelled by key prefix and tracing spans of obtain and release.
+// If tracer is not set, global tracer will be used.
+func Instrument(l Locker, opts ...instrumentOption) (Locker, error) {
+
+	var io = &instrumentOptions{
+		prefix: keyPrefix,
+	}
+
+	for _, opt := range opts {
+		opt(io)
+	}
+
+	var i = &instrumented{
+		Locker: l,
+		tracer: io.tracer,
+		prefix: io.prefix,
+	}
+
+	if i.tracer == nil {
+		i.tracer = opentracing.GlobalTracer()
+	}
+
+	if io.registry != nil {
+		var err error
+
+		i.obtains, err = metrics.NewCount(io.registry,
+			"locker_obtains_total",
+			"Total number of lock obtain attempts.",
+			"prefix", "result",
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		i.wait, err = metrics.NewDuration(io.registry,
+			"locker_wait_seconds",
+			"Duration of lock obtain in seconds.",
+			"prefix",
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		i.hold, err = metrics.NewDuration(io.registry,
+			"locker_hold_seconds",
+			"Duration of lock hold in seconds.",
+			"prefix",
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return i, nil
+}
+
+// Lock method create new mutex lock. Return unlock func or error.
+func (i *instrumented) Lock(ctx context.Context, key string, ttl time.Duration) (UnlockFunc, error) {
+
+	obtained, err := i.Obtain(ctx, key, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		return obtained.Release(ctx)
+	}, nil
+}
+
+// Obtain method create new mutex lock. Return lock handle or error.
+func (i *instrumented) Obtain(ctx context.Context, key string, ttl time.Duration) (Lock, error) {
+
+	var span = i.startSpan(ctx, "locker.Obtain", key)
+	defer span.Finish()
+
+	var start = time.Now()
+
+	lock, err := i.Locker.Obtain(opentracing.ContextWithSpan(ctx, span), key, ttl)
+
+	var (
+		prefix = i.prefix(key)
+		result = resultObtained
+	)
+
+	switch {
+	case errors.Is(err, ErrLockNotObtained):
+		result = resultNotObtained
+	case err != nil:
+		result = resultError
+		ext.Error.Set(span, true)
+		span.SetTag("error.message", err.Error())
+	}
+	span.SetTag("locker.result", result)
+
+	if i.obtains != nil {
+		i.obtains.WithLabelValues(prefix, result).Inc()
+		i.wait.WithLabelValues(prefix).Observe(time.Since(start).Seconds())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &instrumentedLock{
+		Lock:     lock,
+		i:        i,
+		obtained: time.Now(),
+	}, nil
+}
+
+// Release method release lock and record hold time.
+func (l *instrumentedLock) Release(ctx context.Context) error {
+
+	var span = l.i.startSpan(ctx, "locker.Release", l.Key())
+	defer span.Finish()
+
+	err := l.Lock.Release(opentracing.ContextWithSpan(ctx, span))
+	if err != nil && !errors.Is(err, ErrNotLocked) {
+		ext.Error.Set(span, true)
+		span.SetTag("error.message", err.Error())
+	}
+
+	if l.i.hold != nil {
+		l.once.Do(func() {
+			l.i.hold.WithLabelValues(l.i.prefix(l.Key())).Observe(time.Since(l.obtained).Seconds())
+		})
+	}
+
+	return err
+}
+
+// startSpan start locker span, child of context span if any.
+func (i *instrumented) startSpan(ctx context.Context, operation, key string) opentracing.Span {
+
+	var opts []opentracing.StartSpanOption
+	if parent := opentracing.SpanFromContext(ctx); parent != nil {
+		opts = append(opts, opentracing.ChildOf(parent.Context()))
+	}
+
+	var span = i.tracer.StartSpan(operation, opts...)
+	ext.Component.Set(span, componentName)
+	span.SetTag("locker.key", key)
+
+	return span
+}
+
+// keyPrefix return key part before first ':' separator.
+func keyPrefix(key string) string {
+
+	if i := strings.IndexByte(key, ':'); i > 0 {
+		return key[:i]
+	}
+
+	return defaultKeyPrefix
+}
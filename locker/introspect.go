@@ -0,0 +1,327 @@
+package locker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/tarusov/rig/logger"
+)
+
+type (
+	// Owner is lock holder metadata, stored as lock value.
+	Owner struct {
+		ID         string    `json:"id"`          // Random holder id.
+		Hostname   string    `json:"hostname"`    // Holder host.
+		PID        int       `json:"pid"`         // Holder process id.
+		AcquiredAt time.Time `json:"acquired_at"` // Obtain start time.
+	}
+
+	// LockInfo is held lock description.
+	LockInfo struct {
+		Key   string        `json:"key"`
+		Kind  LockKind      `json:"kind"`
+		TTL   time.Duration `json:"ttl"`
+		Owner Owner         `json:"owner"`
+	}
+
+	// LockKind is held lock kind.
+	LockKind string
+)
+
+// LockKind type enum.
+const (
+	LockKindExclusive LockKind = "exclusive" // Mutex or write lock.
+	LockKindShared    LockKind = "shared"    // Read lock or semaphore holder.
+)
+
+// scanCount is keys count hint of list scan iteration.
+const scanCount = 100
+
+// auxSuffixes is suffixes of auxilary keys, which are not locks.
+var auxSuffixes = []string{"fence", "released", "queue", "timeouts", "result", "readers"}
+
+// hostname of current process.
+var hostname, _ = os.Hostname()
+
+// ownerValue generate unique lock value with holder metadata.
+func ownerValue() (string, error) {
+
+	id, err := randomValue()
+	if err != nil {
+		return "", err
+	}
+
+	value, err := json.Marshal(Owner{
+		ID:         id,
+		Hostname:   hostname,
+		PID:        os.Getpid(),
+		AcquiredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode lock value: %w", err)
+	}
+
+	return string(value), nil
+}
+
+// parseOwner decode lock value. Return false if value is not lock value.
+func parseOwner(value string) (Owner, bool) {
+	var o Owner
+	if err := json.Unmarshal([]byte(value), &o); err != nil || o.ID == "" {
+		return Owner{}, false
+	}
+	return o, true
+}
+
+// List method return held locks, which keys start with prefix. Prefix must not
+// be empty. Shared locks are listed by holder, read locks are listed by their
+// lock key. Keys are scanned, so result is not atomic snapshot.
+func (l *RedisLocker) List(ctx context.Context, prefix string) ([]LockInfo, error) {
+
+	if prefix == "" {
+		return nil, errors.New("lock prefix is empty")
+	}
+
+	keys, err := l.scanKeys(ctx, escapePattern(prefix)+"*")
+	if err != nil {
+		return nil, err
+	}
+
+	// Readers of key without hashtag are stored at {key}:readers.
+	readers, err := l.scanKeys(ctx, "{"+escapePattern(prefix)+"*}:readers")
+	if err != nil {
+		return nil, err
+	}
+	keys = append(keys, readers...)
+
+	now, err := l.client.Time(ctx).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get redis time: %w", err)
+	}
+
+	var (
+		infos = make([]LockInfo, 0, len(keys))
+		seen  = make(map[string]bool, len(keys))
+	)
+	for _, key := range keys {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		var lockKey, ok = readersLockKey(key)
+		if !ok {
+			if isAuxKey(key) {
+				continue
+			}
+			lockKey = key
+		}
+
+		kind, err := l.client.Type(ctx, key).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get lock key type: %w", err)
+		}
+
+		switch kind {
+		case "string":
+			value, err := l.client.Get(ctx, key).Result()
+			if err == redis.Nil {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to get lock value: %w", err)
+			}
+
+			owner, ok := parseOwner(value)
+			if !ok {
+				continue
+			}
+
+			ttl, err := l.client.PTTL(ctx, key).Result()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get lock ttl: %w", err)
+			}
+			if ttl <= 0 {
+				continue
+			}
+
+			infos = append(infos, LockInfo{Key: lockKey, Kind: LockKindExclusive, TTL: ttl, Owner: owner})
+
+		case "zset":
+			members, err := l.client.ZRangeWithScores(ctx, key, 0, -1).Result()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get lock holders: %w", err)
+			}
+
+			for _, m := range members {
+				owner, ok := parseOwner(fmt.Sprint(m.Member))
+				if !ok {
+					continue
+				}
+
+				var ttl = time.Duration(int64(m.Score)-now.UnixMilli()) * time.Millisecond
+				if ttl <= 0 {
+					continue
+				}
+
+				infos = append(infos, LockInfo{Key: lockKey, Kind: LockKindShared, TTL: ttl, Owner: owner})
+			}
+		}
+	}
+
+	return infos, nil
+}
+
+// ForceRelease method release lock of key held by owner id, or by any owner
+// if id is empty. Read locks of key are released too. Lock holders are not
+// notified, their refresh will fail. Return number of released holders.
+func (l *RedisLocker) ForceRelease(ctx context.Context, key, id string) (int, error) {
+
+	var target = key
+
+	kind, err := l.client.Type(ctx, target).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get lock key type: %w", err)
+	}
+
+	if kind == "none" {
+		target = subKey(key, "readers")
+		if kind, err = l.client.Type(ctx, target).Result(); err != nil {
+			return 0, fmt.Errorf("failed to get lock key type: %w", err)
+		}
+	}
+
+	var released int
+
+	switch kind {
+	case "string":
+		value, err := l.client.Get(ctx, key).Result()
+		if err == redis.Nil {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to get lock value: %w", err)
+		}
+
+		owner, ok := parseOwner(value)
+		if !ok || (id != "" && owner.ID != id) {
+			return 0, nil
+		}
+
+		res, err := scriptRelease.Run(ctx, l.client, []string{key}, value, subKey(key, "released")).Int()
+		if err != nil {
+			return 0, fmt.Errorf("failed to release lock: %w", err)
+		}
+		released = res
+
+	case "zset":
+		members, err := l.client.ZRange(ctx, target, 0, -1).Result()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get lock holders: %w", err)
+		}
+
+		var matched []interface{}
+		for _, m := range members {
+			if owner, ok := parseOwner(m); ok && (id == "" || owner.ID == id) {
+				matched = append(matched, m)
+			}
+		}
+		if len(matched) == 0 {
+			return 0, nil
+		}
+
+		res, err := l.client.ZRem(ctx, target, matched...).Result()
+		if err != nil {
+			return 0, fmt.Errorf("failed to release lock holders: %w", err)
+		}
+		released = int(res)
+	}
+
+	if released > 0 {
+		logger.FromContext(ctx).WithField("key", key).WithField("owner", id).Warn("lock force released")
+	}
+
+	return released, nil
+}
+
+// scanKeys return keys matching pattern. In cluster mode all masters are scanned.
+func (l *RedisLocker) scanKeys(ctx context.Context, match string) ([]string, error) {
+
+	var (
+		mu   sync.Mutex
+		keys []string
+		scan = func(ctx context.Context, client redis.Cmdable) error {
+			iter := client.Scan(ctx, 0, match, scanCount).Iterator()
+			for iter.Next(ctx) {
+				mu.Lock()
+				keys = append(keys, iter.Val())
+				mu.Unlock()
+			}
+			return iter.Err()
+		}
+		err error
+	)
+
+	if cc, ok := l.client.(*redis.ClusterClient); ok {
+		err = cc.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			return scan(ctx, client)
+		})
+	} else {
+		err = scan(ctx, l.client)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan lock keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// readersLockKey return lock key of readers key.
+func readersLockKey(key string) (string, bool) {
+
+	var base = strings.TrimSuffix(key, ":readers")
+	if base == key {
+		return "", false
+	}
+
+	if strings.HasPrefix(base, "{") && strings.HasSuffix(base, "}") {
+		if lockKey := base[1 : len(base)-1]; subKey(lockKey, "readers") == key {
+			return lockKey, true
+		}
+	}
+	if subKey(base, "readers") == key {
+		return base, true
+	}
+
+	return "", false
+}
+
+// isAuxKey check key is auxilary key of lock.
+func isAuxKey(key string) bool {
+	for _, suffix := range auxSuffixes {
+		if strings.Contains(key, "}") && strings.HasSuffix(key, ":"+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// escapePattern escape glob special characters of redis match pattern.
+func escapePattern(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -1,105 +1,177 @@
-// Package locker contains redis based mutex locker.
+// Package locker contains distributed locks. Locker interface is implemented
+// by redis and redlock drivers of this package and by memory and postgres
+// drivers. Redis driver also provides read/write locks and semaphores.
+//
+// Breaking change: Locker was a redis locker struct and is an interface now.
+// New returns *RedisLocker, so variables and fields of *locker.Locker type
+// must be changed to locker.Locker (or *locker.RedisLocker for redis only
+// methods such as LockWait, ObtainRead and ObtainSemaphore).
 package locker
 
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
-	"github.com/bsm/redislock"
-	"github.com/go-redis/redis/v8"
 	"github.com/tarusov/rig/logger"
 )
 
 type (
-	// Locker struct.
-	Locker struct {
-		client       redis.UniversalClient
-		retryCount   int
-		retryTimeout time.Duration
+	// Locker is distributed mutex locks interface.
+	Locker interface {
+		// Lock obtain mutex lock of key. Return unlock func or error.
+		Lock(ctx context.Context, key string, ttl time.Duration) (UnlockFunc, error)
+		// Obtain obtain mutex lock of key. Return lock handle or error.
+		Obtain(ctx context.Context, key string, ttl time.Duration) (Lock, error)
+	}
+
+	// Lock is obtained lock handle.
+	Lock interface {
+		// Key return locked key.
+		Key() string
+		// Token return lock fencing token. Tokens grow monotonically with each
+		// lock obtain, so storages may reject writes with token less than
+		// already seen. Shared locks (readers, semaphores) have no fencing token.
+		Token() int64
+		// Refresh extend lock ttl. Return ErrNotLocked if lock is lost.
+		Refresh(ctx context.Context, ttl time.Duration) error
+		// TTL return remaining lock ttl. Return ErrNotLocked if lock is lost.
+		TTL(ctx context.Context) (time.Duration, error)
+		// Release release lock. Return ErrNotLocked if lock is lost.
+		Release(ctx context.Context) error
 	}
 
 	// UnlockFunc is method for unlock func.
 	UnlockFunc func() error
 
-	// Target key mutex lock struct.
-	lock struct {
-		*redislock.Lock
-		key string
-		ctx context.Context
+	// TryFunc is single lock obtain attempt. Return false if lock is held.
+	TryFunc func() (bool, error)
+
+	// ctxError is returned if ctx is done before lock is obtained. It wraps
+	// ctx error and matches ErrLockNotObtained.
+	ctxError struct {
+		err error
 	}
 )
 
-// Defaults.
-const (
-	defaultRetryCount   = 3
-	defaultRetryTimeout = 3 * time.Second
+// Aux error types.
+var (
+	ErrLockNotObtained = errors.New("failed to obtain lock")        // Unable to obtain lock.
+	ErrNotLocked       = errors.New("failed to unlock - not exist") // No lock exist.
 )
 
-// New creates new locker instance.
-func New(client redis.UniversalClient, opts ...lockerOption) (*Locker, error) {
+// Retry call try until it succeed, count retries are done or ctx is done.
+// Retries are delayed by timeout. Return ErrLockNotObtained if lock is held,
+// or error wrapping ctx.Err() (it also matches ErrLockNotObtained) if ctx is
+// done.
+func Retry(ctx context.Context, count int, timeout time.Duration, try TryFunc) error {
+	return retry(ctx, count, func() time.Duration { return timeout }, try)
+}
 
-	var l = &Locker{
-		client:       client,
-		retryCount:   defaultRetryCount,
-		retryTimeout: defaultRetryTimeout,
-	}
+// retry is Retry with delay func, which is called before each retry.
+func retry(ctx context.Context, count int, delay func() time.Duration, try TryFunc) error {
 
-	for _, opt := range opts {
-		opt(l)
+	for n := 0; ; n++ {
+		ok, err := try()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+
+		if n >= count {
+			return ErrLockNotObtained
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctxError{err: ctx.Err()}
+		case <-time.After(delay()):
+		}
 	}
+}
 
-	return l, nil
+// Error implements error interface.
+func (e ctxError) Error() string {
+	return ErrLockNotObtained.Error() + ": " + e.err.Error()
 }
 
-// Aux error types.
-var (
-	ErrLockNotObtained = errors.New("failed to obtain lock")        // Unable to obtain lock.
-	ErrNotLocked       = errors.New("failed to unlock - not exist") // No lock exist.
-)
+// Unwrap return ctx error.
+func (e ctxError) Unwrap() error {
+	return e.err
+}
 
-// Lock method create new redis mutex lock. Return unlock func or error.
-func (l *Locker) Lock(ctx context.Context, key string, ttl time.Duration) (UnlockFunc, error) {
-
-	obtained, err := redislock.Obtain(
-		ctx,
-		l.client,
-		key,
-		ttl,
-		&redislock.Options{
-			RetryStrategy: redislock.LimitRetry(redislock.LinearBackoff(l.retryTimeout), l.retryCount),
-		},
-	)
-	if err != nil {
-		if err == redislock.ErrNotObtained {
-			return nil, ErrLockNotObtained
-		}
-		return nil, err
+// Is match ErrLockNotObtained.
+func (e ctxError) Is(target error) bool {
+	return target == ErrLockNotObtained
+}
+
+// CheckTTL reject lock ttl, which is less than millisecond precision of
+// drivers. Drivers check ttl on obtain.
+func CheckTTL(ttl time.Duration) error {
+	if ttl < time.Millisecond {
+		return fmt.Errorf("invalid lock ttl: %v", ttl)
 	}
+	return nil
+}
 
-	logger.FromContext(ctx).WithField("key", key).Debug("lock obtained")
+// AutoRefresh extend lock for ttl every ttl/3 in background, until returned
+// cancel func is called. Returned context is cancelled if lock is lost: it is
+// taken by other holder or not refreshed during ttl. Cancel does not release lock.
+// If ttl is invalid, returned context is already cancelled.
+func AutoRefresh(ctx context.Context, l Lock, ttl time.Duration) (context.Context, context.CancelFunc) {
 
-	r := lock{
-		Lock: obtained,
-		key:  key,
-		ctx:  ctx,
-	}
+	var (
+		rCtx, rCancel = context.WithCancel(ctx)
+		done          = make(chan struct{})
+	)
 
-	return func() error {
-		return r.Unlock()
-	}, nil
-}
+	if err := CheckTTL(ttl); err != nil {
+		logger.FromContext(ctx).WithField("key", l.Key()).WithErr(err).Error("failed to refresh lock")
+		rCancel()
+		return rCtx, rCancel
+	}
 
-// Unlock method try to release current mutex.
-func (l *lock) Unlock() (err error) {
-	defer func() {
-		logger.FromContext(l.ctx).WithField("key", l.key).WithErr(err).Debug("unlocked")
+	go func() {
+		defer close(done)
+		defer rCancel()
+
+		var (
+			ticker   = time.NewTicker(ttl / 3)
+			deadline = time.Now().Add(ttl)
+			log      = logger.FromContext(rCtx).WithField("key", l.Key())
+		)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-rCtx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			err := l.Refresh(rCtx, ttl)
+			if err == nil {
+				deadline = time.Now().Add(ttl)
+				continue
+			}
+			if rCtx.Err() != nil {
+				return
+			}
+
+			if errors.Is(err, ErrNotLocked) || time.Now().After(deadline) {
+				log.WithErr(err).Warn("lock lost")
+				return
+			}
+
+			log.WithErr(err).Warn("failed to refresh lock")
+		}
 	}()
 
-	err = l.Lock.Release(l.ctx)
-	if err == redislock.ErrLockNotHeld {
-		return ErrNotLocked
+	return rCtx, func() {
+		rCancel()
+		<-done
 	}
-
-	return err
 }
@@ -2,21 +2,117 @@ package locker
 
 import (
 	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/tarusov/rig/metrics"
 )
 
-// lockerOption is locker constructor optional modificator.
-type lockerOption func(*Locker)
+type (
+	// lockerOption is locker constructor optional modificator.
+	lockerOption func(*lockerOptions)
+
+	// lockerOptions is auxilary constructor struct.
+	lockerOptions struct {
+		retryCount   int
+		retryTimeout time.Duration
+		driftFactor  float64
+	}
+)
 
 // WithRetryCount set custom retry count.
 func WithRetryCount(n int) lockerOption {
-	return func(l *Locker) {
-		l.retryCount = n
+	return func(lo *lockerOptions) {
+		lo.retryCount = n
 	}
 }
 
 // WithRetryTimeout set custom retry timeout.
 func WithRetryTimeout(t time.Duration) lockerOption {
-	return func(l *Locker) {
-		l.retryTimeout = t
+	return func(lo *lockerOptions) {
+		lo.retryTimeout = t
+	}
+}
+
+// WithDriftFactor set redlock clock drift factor, lock validity is reduced
+// by ttl*factor (0.01 by default).
+func WithDriftFactor(f float64) lockerOption {
+	return func(lo *lockerOptions) {
+		lo.driftFactor = f
+	}
+}
+
+type (
+	// instrumentOption is Instrument optional modificator.
+	instrumentOption func(*instrumentOptions)
+
+	// instrumentOptions is auxilary Instrument struct.
+	instrumentOptions struct {
+		registry metrics.Registry
+		tracer   opentracing.Tracer
+		prefix   func(key string) string
+	}
+)
+
+// WithMetrics setup registry for lock metrics.
+func WithMetrics(registry metrics.Registry) instrumentOption {
+	return func(io *instrumentOptions) {
+		io.registry = registry
+	}
+}
+
+// WithTracer setup tracer for lock spans.
+func WithTracer(tracer opentracing.Tracer) instrumentOption {
+	return func(io *instrumentOptions) {
+		io.tracer = tracer
+	}
+}
+
+// WithKeyPrefix setup func, which return metrics label of key. By default
+// it is key part before first ':' separator.
+func WithKeyPrefix(fn func(key string) string) instrumentOption {
+	return func(io *instrumentOptions) {
+		io.prefix = fn
+	}
+}
+
+type (
+	// singleflightOption is singleflight constructor optional modificator.
+	singleflightOption func(*singleflightOptions)
+
+	// singleflightOptions is auxilary constructor struct.
+	singleflightOptions struct {
+		lockTTL   time.Duration
+		resultTTL time.Duration
+	}
+)
+
+// WithFlightLockTTL set ttl of lock held during call, it is auto refreshed.
+func WithFlightLockTTL(ttl time.Duration) singleflightOption {
+	return func(so *singleflightOptions) {
+		so.lockTTL = ttl
+	}
+}
+
+// WithFlightResultTTL set ttl of cached call result, it must be positive.
+func WithFlightResultTTL(ttl time.Duration) singleflightOption {
+	return func(so *singleflightOptions) {
+		so.resultTTL = ttl
+	}
+}
+
+type (
+	// adminOption is AdminHandler optional modificator.
+	adminOption func(*adminOptions)
+
+	// adminOptions is auxilary AdminHandler struct.
+	adminOptions struct {
+		forceRelease bool
+	}
+)
+
+// WithForceRelease allow force release of locks by admin handler.
+func WithForceRelease() adminOption {
+	return func(ao *adminOptions) {
+		ao.forceRelease = true
 	}
 }
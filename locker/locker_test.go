@@ -2,86 +2,48 @@ package locker_test
 
 import (
 	"context"
-	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/internal/testcontainer"
 	"github.com/tarusov/rig/locker"
-	"github.com/testcontainers/testcontainers-go"
-	"github.com/testcontainers/testcontainers-go/wait"
+	"github.com/tarusov/rig/locker/lockertest"
 )
 
-type redisContainer struct {
-	testcontainers.Container
-	URI string
-}
-
-func setupRedis(ctx context.Context) (*redisContainer, error) {
-
-	req := testcontainers.ContainerRequest{
-		Image:        "redis:6",
-		ExposedPorts: []string{"6379/tcp"},
-		WaitingFor:   wait.ForLog("* Ready to accept connections"),
-	}
-	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
-		ContainerRequest: req,
-		Started:          true,
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	mappedPort, err := container.MappedPort(ctx, "6379")
-	if err != nil {
-		return nil, err
-	}
-
-	hostIP, err := container.Host(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	uri := fmt.Sprintf("redis://%s:%s", hostIP, mappedPort.Port())
-
-	return &redisContainer{Container: container, URI: uri}, nil
-}
-
-// redisServer for tests.
-var redisServer *redisContainer
+// redisURI for tests. External server may be set with REDIS_URI env.
+var redisURI string
 
 func TestMain(m *testing.M) {
 
-	var (
-		ctx = context.Background()
-		err error
-	)
-
-	redisServer, err = setupRedis(ctx)
+	uri, terminate, err := testcontainer.Redis(context.Background())
 	if err != nil {
 		log.Println("redis container not inited", err)
 		os.Exit(1)
 	}
-	defer func() {
-		if err = redisServer.Terminate(ctx); err != nil {
-			log.Println("failed to terminate redis container", err)
-		}
-	}()
+	redisURI = uri
 
-	os.Exit(m.Run())
+	var code = m.Run()
+	terminate()
+	os.Exit(code)
 }
 
 func TestLockMultipleTries(t *testing.T) {
 
-	if redisServer == nil {
+	if redisURI == "" {
 		t.Skip("server not inited")
 	}
 
-	opts, err := redis.ParseURL(redisServer.URI)
+	opts, err := redis.ParseURL(redisURI)
 	require.ErrorIsf(t, err, nil, "TestLockMultipleTries: unexpected parse uri: %v", err)
 
 	lockerClient, err := locker.New(
@@ -106,13 +68,49 @@ func TestLockMultipleTries(t *testing.T) {
 	require.ErrorIsf(t, err, locker.ErrLockNotObtained, "TestLockMultipleTries: unexpected error: %v", err)
 }
 
+func TestLockContextDone(t *testing.T) {
+
+	if redisURI == "" {
+		t.Skip("server not inited")
+	}
+
+	opts, err := redis.ParseURL(redisURI)
+	require.ErrorIsf(t, err, nil, "TestLockContextDone: unexpected parse uri: %v", err)
+
+	lockerClient, err := locker.New(
+		redis.NewClient(opts),
+		locker.WithRetryCount(10),
+		locker.WithRetryTimeout(time.Second),
+	)
+	require.ErrorIsf(t, err, nil, "TestLockContextDone: unexpected locker error: %v", err)
+
+	var (
+		ctx = context.Background()
+		key = uuid.NewString()
+	)
+
+	_, err = lockerClient.Obtain(ctx, key, time.Microsecond)
+	require.Errorf(t, err, "TestLockContextDone: sub millisecond ttl accepted")
+
+	lock, err := lockerClient.Obtain(ctx, key, time.Second)
+	require.ErrorIsf(t, err, nil, "TestLockContextDone: unexpected obtain error: %v", err)
+	defer lock.Release(ctx)
+
+	cCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	_, err = lockerClient.Obtain(cCtx, key, time.Second)
+	require.ErrorIsf(t, err, context.DeadlineExceeded, "TestLockContextDone: unexpected obtain error: %v", err)
+	require.ErrorIsf(t, err, locker.ErrLockNotObtained, "TestLockContextDone: unexpected obtain error: %v", err)
+}
+
 func TestLockTTL(t *testing.T) {
 
-	if redisServer == nil {
+	if redisURI == "" {
 		t.Skip("server not inited")
 	}
 
-	opts, err := redis.ParseURL(redisServer.URI)
+	opts, err := redis.ParseURL(redisURI)
 	require.ErrorIsf(t, err, nil, "TestLockTTL: unexpected parse uri: %v", err)
 
 	lockerClient, err := locker.New(
@@ -140,3 +138,541 @@ func TestLockTTL(t *testing.T) {
 	err = unlock()
 	require.ErrorIsf(t, err, locker.ErrNotLocked, "TestLockTTL: unexpected lock error: %v", err)
 }
+
+func TestLockAutoRefresh(t *testing.T) {
+
+	if redisURI == "" {
+		t.Skip("server not inited")
+	}
+
+	opts, err := redis.ParseURL(redisURI)
+	require.ErrorIsf(t, err, nil, "TestLockAutoRefresh: unexpected parse uri: %v", err)
+
+	var client = redis.NewClient(opts)
+
+	lockerClient, err := locker.New(client, locker.WithRetryCount(0))
+	require.ErrorIsf(t, err, nil, "TestLockAutoRefresh: unexpected locker error: %v", err)
+
+	var (
+		ctx = context.Background()
+		key = uuid.NewString()
+		ttl = 300 * time.Millisecond
+	)
+	lock, err := lockerClient.Obtain(ctx, key, ttl)
+	require.ErrorIsf(t, err, nil, "TestLockAutoRefresh: unexpected lock error: %v", err)
+	require.Equalf(t, key, lock.Key(), "TestLockAutoRefresh: unexpected lock key")
+
+	rCtx, cancel := locker.AutoRefresh(ctx, lock, ttl)
+	defer cancel()
+
+	// Lock is held longer than ttl.
+	time.Sleep(3 * ttl)
+
+	_, err = lockerClient.Lock(ctx, key, ttl)
+	require.ErrorIsf(t, err, locker.ErrLockNotObtained, "TestLockAutoRefresh: unexpected lock error: %v", err)
+
+	left, err := lock.TTL(ctx)
+	require.ErrorIsf(t, err, nil, "TestLockAutoRefresh: unexpected ttl error: %v", err)
+	require.Greaterf(t, left, time.Duration(0), "TestLockAutoRefresh: unexpected ttl: %v", left)
+
+	// Simulate lock expiration.
+	err = client.Del(ctx, key).Err()
+	require.ErrorIsf(t, err, nil, "TestLockAutoRefresh: unexpected redis error: %v", err)
+
+	select {
+	case <-rCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("TestLockAutoRefresh: lock lost is not detected")
+	}
+
+	_, err = lock.TTL(ctx)
+	require.ErrorIsf(t, err, locker.ErrNotLocked, "TestLockAutoRefresh: unexpected ttl error: %v", err)
+
+	err = lock.Release(ctx)
+	require.ErrorIsf(t, err, locker.ErrNotLocked, "TestLockAutoRefresh: unexpected release error: %v", err)
+}
+
+func TestLockFencingToken(t *testing.T) {
+
+	if redisURI == "" {
+		t.Skip("server not inited")
+	}
+
+	opts, err := redis.ParseURL(redisURI)
+	require.ErrorIsf(t, err, nil, "TestLockFencingToken: unexpected parse uri: %v", err)
+
+	lockerClient, err := locker.New(redis.NewClient(opts), locker.WithRetryCount(0))
+	require.ErrorIsf(t, err, nil, "TestLockFencingToken: unexpected locker error: %v", err)
+
+	var ctx = context.Background()
+
+	for _, key := range []string{uuid.NewString(), "{" + uuid.NewString() + "}:job"} {
+		var prev int64
+
+		for i := 0; i < 3; i++ {
+			lock, err := lockerClient.Obtain(ctx, key, time.Second)
+			require.ErrorIsf(t, err, nil, "TestLockFencingToken: unexpected lock error: %v", err)
+			require.Greaterf(t, lock.Token(), prev, "TestLockFencingToken: token is not increased")
+			prev = lock.Token()
+
+			err = lock.Release(ctx)
+			require.ErrorIsf(t, err, nil, "TestLockFencingToken: unexpected release error: %v", err)
+		}
+	}
+}
+
+func TestRWLock(t *testing.T) {
+
+	if redisURI == "" {
+		t.Skip("server not inited")
+	}
+
+	opts, err := redis.ParseURL(redisURI)
+	require.ErrorIsf(t, err, nil, "TestRWLock: unexpected parse uri: %v", err)
+
+	lockerClient, err := locker.New(redis.NewClient(opts), locker.WithRetryCount(0))
+	require.ErrorIsf(t, err, nil, "TestRWLock: unexpected locker error: %v", err)
+
+	var (
+		ctx = context.Background()
+		key = uuid.NewString()
+	)
+
+	_, err = lockerClient.ObtainRead(ctx, key, 0)
+	require.Errorf(t, err, "TestRWLock: invalid read lock ttl is accepted")
+
+	_, err = lockerClient.ObtainWrite(ctx, key, 0)
+	require.Errorf(t, err, "TestRWLock: invalid write lock ttl is accepted")
+
+	first, err := lockerClient.ObtainRead(ctx, key, time.Second)
+	require.ErrorIsf(t, err, nil, "TestRWLock: unexpected read lock error: %v", err)
+
+	second, err := lockerClient.ObtainRead(ctx, key, time.Second)
+	require.ErrorIsf(t, err, nil, "TestRWLock: unexpected read lock error: %v", err)
+
+	_, err = lockerClient.ObtainWrite(ctx, key, time.Second)
+	require.ErrorIsf(t, err, locker.ErrLockNotObtained, "TestRWLock: unexpected write lock error: %v", err)
+
+	err = first.Refresh(ctx, 2*time.Second)
+	require.ErrorIsf(t, err, nil, "TestRWLock: unexpected refresh error: %v", err)
+
+	left, err := first.TTL(ctx)
+	require.ErrorIsf(t, err, nil, "TestRWLock: unexpected ttl error: %v", err)
+	require.Greaterf(t, left, time.Second, "TestRWLock: unexpected ttl: %v", left)
+
+	for _, lock := range []locker.Lock{first, second} {
+		err = lock.Release(ctx)
+		require.ErrorIsf(t, err, nil, "TestRWLock: unexpected release error: %v", err)
+	}
+
+	err = first.Release(ctx)
+	require.ErrorIsf(t, err, locker.ErrNotLocked, "TestRWLock: unexpected release error: %v", err)
+
+	writer, err := lockerClient.ObtainWrite(ctx, key, time.Second)
+	require.ErrorIsf(t, err, nil, "TestRWLock: unexpected write lock error: %v", err)
+	require.Greaterf(t, writer.Token(), int64(0), "TestRWLock: write lock has no fencing token")
+
+	_, err = lockerClient.ObtainRead(ctx, key, time.Second)
+	require.ErrorIsf(t, err, locker.ErrLockNotObtained, "TestRWLock: unexpected read lock error: %v", err)
+
+	err = writer.Release(ctx)
+	require.ErrorIsf(t, err, nil, "TestRWLock: unexpected release error: %v", err)
+}
+
+func TestSemaphore(t *testing.T) {
+
+	if redisURI == "" {
+		t.Skip("server not inited")
+	}
+
+	opts, err := redis.ParseURL(redisURI)
+	require.ErrorIsf(t, err, nil, "TestSemaphore: unexpected parse uri: %v", err)
+
+	lockerClient, err := locker.New(redis.NewClient(opts), locker.WithRetryCount(0))
+	require.ErrorIsf(t, err, nil, "TestSemaphore: unexpected locker error: %v", err)
+
+	var (
+		ctx = context.Background()
+		key = uuid.NewString()
+	)
+
+	_, err = lockerClient.ObtainSemaphore(ctx, key, 0, time.Second)
+	require.Errorf(t, err, "TestSemaphore: invalid limit is accepted")
+
+	_, err = lockerClient.ObtainSemaphore(ctx, key, 2, 0)
+	require.Errorf(t, err, "TestSemaphore: invalid ttl is accepted")
+
+	first, err := lockerClient.ObtainSemaphore(ctx, key, 2, time.Second)
+	require.ErrorIsf(t, err, nil, "TestSemaphore: unexpected obtain error: %v", err)
+
+	_, err = lockerClient.ObtainSemaphore(ctx, key, 2, 500*time.Millisecond)
+	require.ErrorIsf(t, err, nil, "TestSemaphore: unexpected obtain error: %v", err)
+
+	_, err = lockerClient.ObtainSemaphore(ctx, key, 2, time.Second)
+	require.ErrorIsf(t, err, locker.ErrLockNotObtained, "TestSemaphore: unexpected obtain error: %v", err)
+
+	err = first.Release(ctx)
+	require.ErrorIsf(t, err, nil, "TestSemaphore: unexpected release error: %v", err)
+
+	third, err := lockerClient.ObtainSemaphore(ctx, key, 2, time.Second)
+	require.ErrorIsf(t, err, nil, "TestSemaphore: unexpected obtain error: %v", err)
+
+	// Expired holder frees its slot.
+	time.Sleep(600 * time.Millisecond)
+
+	fourth, err := lockerClient.ObtainSemaphore(ctx, key, 2, time.Second)
+	require.ErrorIsf(t, err, nil, "TestSemaphore: unexpected obtain error: %v", err)
+
+	for _, lock := range []locker.Lock{third, fourth} {
+		err = lock.Release(ctx)
+		require.ErrorIsf(t, err, nil, "TestSemaphore: unexpected release error: %v", err)
+	}
+}
+
+func TestConformance(t *testing.T) {
+
+	if redisURI == "" {
+		t.Skip("server not inited")
+	}
+
+	opts, err := redis.ParseURL(redisURI)
+	require.ErrorIsf(t, err, nil, "TestConformance: unexpected parse uri: %v", err)
+
+	lockertest.Run(t, func(t *testing.T) locker.Locker {
+		l, err := locker.New(redis.NewClient(opts), locker.WithRetryCount(0))
+		require.ErrorIsf(t, err, nil, "TestConformance: unexpected locker error: %v", err)
+		return l
+	})
+}
+
+// redlockClients return clients of independent nodes. Test nodes are
+// emulated by databases of one server.
+func redlockClients(t *testing.T, n int) []redis.UniversalClient {
+
+	var clients []redis.UniversalClient
+
+	for i := 0; i < n; i++ {
+		opts, err := redis.ParseURL(redisURI)
+		require.ErrorIsf(t, err, nil, "%s: unexpected parse uri: %v", t.Name(), err)
+
+		opts.DB = i
+		clients = append(clients, redis.NewClient(opts))
+	}
+
+	return clients
+}
+
+func TestRedlockConformance(t *testing.T) {
+
+	if redisURI == "" {
+		t.Skip("server not inited")
+	}
+
+	var clients = redlockClients(t, 3)
+
+	lockertest.Run(t, func(t *testing.T) locker.Locker {
+		l, err := locker.NewRedlock(clients, locker.WithRetryCount(0))
+		require.ErrorIsf(t, err, nil, "TestRedlockConformance: unexpected locker error: %v", err)
+		return l
+	})
+}
+
+func TestRedlockQuorum(t *testing.T) {
+
+	if redisURI == "" {
+		t.Skip("server not inited")
+	}
+
+	var clients = redlockClients(t, 3)
+
+	_, err := locker.NewRedlock(nil)
+	require.Errorf(t, err, "TestRedlockQuorum: empty clients are accepted")
+
+	lockerClient, err := locker.NewRedlock(clients, locker.WithRetryCount(0))
+	require.ErrorIsf(t, err, nil, "TestRedlockQuorum: unexpected locker error: %v", err)
+
+	var (
+		ctx = context.Background()
+		key = uuid.NewString()
+	)
+
+	_, err = lockerClient.Obtain(ctx, key, time.Microsecond)
+	require.Errorf(t, err, "TestRedlockQuorum: sub millisecond ttl accepted")
+
+	// Key is held on minority of nodes.
+	err = clients[0].Set(ctx, key, "other", time.Minute).Err()
+	require.ErrorIsf(t, err, nil, "TestRedlockQuorum: unexpected redis error: %v", err)
+
+	lock, err := lockerClient.Obtain(ctx, key, time.Second)
+	require.ErrorIsf(t, err, nil, "TestRedlockQuorum: unexpected obtain error: %v", err)
+
+	left, err := lock.TTL(ctx)
+	require.ErrorIsf(t, err, nil, "TestRedlockQuorum: unexpected ttl error: %v", err)
+	require.Greaterf(t, left, time.Duration(0), "TestRedlockQuorum: unexpected ttl: %v", left)
+
+	err = lock.Release(ctx)
+	require.ErrorIsf(t, err, nil, "TestRedlockQuorum: unexpected release error: %v", err)
+
+	// Key is held on majority of nodes.
+	err = clients[1].Set(ctx, key, "other", time.Minute).Err()
+	require.ErrorIsf(t, err, nil, "TestRedlockQuorum: unexpected redis error: %v", err)
+
+	_, err = lockerClient.Obtain(ctx, key, time.Second)
+	require.ErrorIsf(t, err, locker.ErrLockNotObtained, "TestRedlockQuorum: unexpected obtain error: %v", err)
+
+	// Partially obtained lock is released.
+	exists, err := clients[2].Exists(ctx, key).Result()
+	require.ErrorIsf(t, err, nil, "TestRedlockQuorum: unexpected redis error: %v", err)
+	require.Equalf(t, int64(0), exists, "TestRedlockQuorum: partial lock is not released")
+}
+
+func TestLockWait(t *testing.T) {
+
+	if redisURI == "" {
+		t.Skip("server not inited")
+	}
+
+	opts, err := redis.ParseURL(redisURI)
+	require.ErrorIsf(t, err, nil, "TestLockWait: unexpected parse uri: %v", err)
+
+	lockerClient, err := locker.New(redis.NewClient(opts), locker.WithRetryCount(0))
+	require.ErrorIsf(t, err, nil, "TestLockWait: unexpected locker error: %v", err)
+
+	var (
+		ctx = context.Background()
+		key = uuid.NewString()
+	)
+
+	_, err = lockerClient.LockWait(ctx, key, 0)
+	require.Errorf(t, err, "TestLockWait: invalid ttl is accepted")
+
+	holder, err := lockerClient.LockWait(ctx, key, time.Minute)
+	require.ErrorIsf(t, err, nil, "TestLockWait: unexpected lock error: %v", err)
+
+	// Cancelled waiter leaves queue.
+	cCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+
+	_, err = lockerClient.LockWait(cCtx, key, time.Minute)
+	require.ErrorIsf(t, err, locker.ErrLockNotObtained, "TestLockWait: unexpected lock error: %v", err)
+	require.ErrorIsf(t, err, context.DeadlineExceeded, "TestLockWait: unexpected lock error: %v", err)
+
+	var (
+		order = make(chan int, 3)
+		errs  = make(chan error, 3)
+	)
+
+	for i := 0; i < 3; i++ {
+		go func(i int) {
+			lock, err := lockerClient.LockWait(ctx, key, time.Minute)
+			if err != nil {
+				errs <- err
+				return
+			}
+			order <- i
+			time.Sleep(10 * time.Millisecond)
+			errs <- lock.Release(ctx)
+		}(i)
+
+		// Let waiter take place in queue.
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	var start = time.Now()
+
+	err = holder.Release(ctx)
+	require.ErrorIsf(t, err, nil, "TestLockWait: unexpected release error: %v", err)
+
+	for i := 0; i < 3; i++ {
+		select {
+		case err = <-errs:
+			require.ErrorIsf(t, err, nil, "TestLockWait: unexpected waiter error: %v", err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("TestLockWait: waiter is not notified")
+		}
+		require.Equalf(t, i, <-order, "TestLockWait: waiters order is not fair")
+	}
+
+	require.Lessf(t, time.Since(start), time.Second, "TestLockWait: waiters are not notified on release")
+}
+
+func TestSingleflight(t *testing.T) {
+
+	if redisURI == "" {
+		t.Skip("server not inited")
+	}
+
+	opts, err := redis.ParseURL(redisURI)
+	require.ErrorIsf(t, err, nil, "TestSingleflight: unexpected parse uri: %v", err)
+
+	var (
+		ctx   = context.Background()
+		key   = uuid.NewString()
+		calls int32
+		wg    sync.WaitGroup
+	)
+
+	// Each caller emulates separate instance.
+	for i := 0; i < 3; i++ {
+		lockerClient, err := locker.New(redis.NewClient(opts))
+		require.ErrorIsf(t, err, nil, "TestSingleflight: unexpected locker error: %v", err)
+
+		sf, err := locker.NewSingleflight(lockerClient)
+		require.ErrorIsf(t, err, nil, "TestSingleflight: unexpected singleflight error: %v", err)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			res, err := sf.Do(ctx, key, func(context.Context) ([]byte, error) {
+				atomic.AddInt32(&calls, 1)
+				time.Sleep(100 * time.Millisecond)
+				return []byte("result"), nil
+			})
+			assert.ErrorIsf(t, err, nil, "TestSingleflight: unexpected do error: %v", err)
+			assert.Equalf(t, "result", string(res), "TestSingleflight: unexpected result")
+		}()
+	}
+
+	wg.Wait()
+
+	require.Equalf(t, int32(1), atomic.LoadInt32(&calls), "TestSingleflight: calls are not deduplicated")
+
+	lockerClient, err := locker.New(redis.NewClient(opts))
+	require.ErrorIsf(t, err, nil, "TestSingleflight: unexpected locker error: %v", err)
+
+	_, err = locker.NewSingleflight(lockerClient, locker.WithFlightResultTTL(0))
+	require.Errorf(t, err, "TestSingleflight: zero result ttl accepted")
+
+	// Call lock does not hold key.
+	lock, err := lockerClient.Obtain(ctx, key, time.Second)
+	require.ErrorIsf(t, err, nil, "TestSingleflight: unexpected obtain error: %v", err)
+	defer lock.Release(ctx)
+
+	sf, err := locker.NewSingleflight(lockerClient)
+	require.ErrorIsf(t, err, nil, "TestSingleflight: unexpected singleflight error: %v", err)
+
+	err = sf.Forget(ctx, key)
+	require.ErrorIsf(t, err, nil, "TestSingleflight: unexpected forget error: %v", err)
+
+	res, err := sf.Do(ctx, key, func(context.Context) ([]byte, error) {
+		return []byte("held"), nil
+	})
+	require.ErrorIsf(t, err, nil, "TestSingleflight: unexpected do error: %v", err)
+	require.Equalf(t, "held", string(res), "TestSingleflight: unexpected result")
+}
+
+func TestList(t *testing.T) {
+
+	if redisURI == "" {
+		t.Skip("server not inited")
+	}
+
+	opts, err := redis.ParseURL(redisURI)
+	require.ErrorIsf(t, err, nil, "TestList: unexpected parse uri: %v", err)
+
+	lockerClient, err := locker.New(redis.NewClient(opts), locker.WithRetryCount(0))
+	require.ErrorIsf(t, err, nil, "TestList: unexpected locker error: %v", err)
+
+	var (
+		ctx    = context.Background()
+		prefix = uuid.NewString() + ":"
+	)
+
+	mutex, err := lockerClient.Obtain(ctx, prefix+"mutex", time.Minute)
+	require.ErrorIsf(t, err, nil, "TestList: unexpected obtain error: %v", err)
+
+	_, err = lockerClient.ObtainSemaphore(ctx, prefix+"semaphore", 2, time.Minute)
+	require.ErrorIsf(t, err, nil, "TestList: unexpected obtain error: %v", err)
+
+	_, err = lockerClient.ObtainSemaphore(ctx, prefix+"semaphore", 2, time.Minute)
+	require.ErrorIsf(t, err, nil, "TestList: unexpected obtain error: %v", err)
+
+	reader, err := lockerClient.ObtainRead(ctx, prefix+"rw", time.Minute)
+	require.ErrorIsf(t, err, nil, "TestList: unexpected obtain read error: %v", err)
+
+	_, err = lockerClient.List(ctx, "")
+	require.Errorf(t, err, "TestList: empty prefix accepted")
+
+	infos, err := lockerClient.List(ctx, prefix)
+	require.ErrorIsf(t, err, nil, "TestList: unexpected list error: %v", err)
+	require.Lenf(t, infos, 4, "TestList: unexpected locks count")
+
+	hostname, _ := os.Hostname()
+
+	var holders = make(map[string][]locker.LockInfo)
+	for _, info := range infos {
+		require.Equalf(t, hostname, info.Owner.Hostname, "TestList: unexpected owner hostname")
+		require.Equalf(t, os.Getpid(), info.Owner.PID, "TestList: unexpected owner pid")
+		require.Truef(t, info.TTL > 0 && info.TTL <= time.Minute, "TestList: unexpected ttl: %v", info.TTL)
+		holders[info.Key] = append(holders[info.Key], info)
+	}
+	require.Equalf(t, locker.LockKindExclusive, holders[prefix+"mutex"][0].Kind, "TestList: unexpected mutex kind")
+	require.Lenf(t, holders[prefix+"semaphore"], 2, "TestList: unexpected semaphore holders")
+	require.Lenf(t, holders[prefix+"rw"], 1, "TestList: read lock not listed by lock key")
+	require.Equalf(t, locker.LockKindShared, holders[prefix+"rw"][0].Kind, "TestList: unexpected read lock kind")
+
+	// Force release of one semaphore holder by owner id.
+	released, err := lockerClient.ForceRelease(ctx, prefix+"semaphore", holders[prefix+"semaphore"][0].Owner.ID)
+	require.ErrorIsf(t, err, nil, "TestList: unexpected force release error: %v", err)
+	require.Equalf(t, 1, released, "TestList: unexpected released count")
+
+	released, err = lockerClient.ForceRelease(ctx, prefix+"mutex", "")
+	require.ErrorIsf(t, err, nil, "TestList: unexpected force release error: %v", err)
+	require.Equalf(t, 1, released, "TestList: unexpected released count")
+
+	_, err = mutex.TTL(ctx)
+	require.ErrorIsf(t, err, locker.ErrNotLocked, "TestList: force released lock is held: %v", err)
+
+	released, err = lockerClient.ForceRelease(ctx, prefix+"rw", "")
+	require.ErrorIsf(t, err, nil, "TestList: unexpected force release error: %v", err)
+	require.Equalf(t, 1, released, "TestList: unexpected released count")
+
+	_, err = reader.TTL(ctx)
+	require.ErrorIsf(t, err, locker.ErrNotLocked, "TestList: force released read lock is held: %v", err)
+
+	infos, err = lockerClient.List(ctx, prefix)
+	require.ErrorIsf(t, err, nil, "TestList: unexpected list error: %v", err)
+	require.Lenf(t, infos, 1, "TestList: unexpected locks count after release")
+}
+
+func TestAdminHandler(t *testing.T) {
+
+	if redisURI == "" {
+		t.Skip("server not inited")
+	}
+
+	opts, err := redis.ParseURL(redisURI)
+	require.ErrorIsf(t, err, nil, "TestAdminHandler: unexpected parse uri: %v", err)
+
+	lockerClient, err := locker.New(redis.NewClient(opts), locker.WithRetryCount(0))
+	require.ErrorIsf(t, err, nil, "TestAdminHandler: unexpected locker error: %v", err)
+
+	var (
+		ctx = context.Background()
+		key = uuid.NewString()
+	)
+
+	_, err = lockerClient.Obtain(ctx, key, time.Minute)
+	require.ErrorIsf(t, err, nil, "TestAdminHandler: unexpected obtain error: %v", err)
+
+	var rec = httptest.NewRecorder()
+	locker.AdminHandler(lockerClient).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equalf(t, http.StatusBadRequest, rec.Code, "TestAdminHandler: list without prefix is allowed")
+
+	rec = httptest.NewRecorder()
+	locker.AdminHandler(lockerClient).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?prefix="+key, nil))
+	require.Equalf(t, http.StatusOK, rec.Code, "TestAdminHandler: unexpected list status")
+	require.Containsf(t, rec.Body.String(), key, "TestAdminHandler: lock not listed")
+
+	var release = httptest.NewRequest(http.MethodDelete, "/?key="+key, nil)
+
+	rec = httptest.NewRecorder()
+	locker.AdminHandler(lockerClient).ServeHTTP(rec, release)
+	require.Equalf(t, http.StatusForbidden, rec.Code, "TestAdminHandler: force release is not forbidden")
+
+	rec = httptest.NewRecorder()
+	locker.AdminHandler(lockerClient, locker.WithForceRelease()).ServeHTTP(rec, release)
+	require.Equalf(t, http.StatusOK, rec.Code, "TestAdminHandler: unexpected release status")
+	require.JSONEqf(t, `{"released":1}`, rec.Body.String(), "TestAdminHandler: unexpected release response")
+}
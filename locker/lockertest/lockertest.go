@@ -0,0 +1,225 @@
+// Package lockertest contains conformance test suite for locker drivers.
+package lockertest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/locker"
+)
+
+// NewFunc creates locker driver for test. Driver should not retry obtain.
+type NewFunc func(t *testing.T) locker.Locker
+
+// Run run conformance tests for locker driver.
+func Run(t *testing.T, newLocker NewFunc) {
+
+	var tests = []struct {
+		name string
+		fn   func(t *testing.T, l locker.Locker)
+	}{
+		{"Exclusive", testExclusive},
+		{"Release", testRelease},
+		{"Expire", testExpire},
+		{"Refresh", testRefresh},
+		{"FencingToken", testFencingToken},
+		{"AutoRefresh", testAutoRefresh},
+		{"InvalidTTL", testInvalidTTL},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newLocker(t))
+		})
+	}
+}
+
+// testExclusive check lock can't be obtained twice, while other keys are free.
+func testExclusive(t *testing.T, l locker.Locker) {
+
+	var (
+		ctx = context.Background()
+		key = uuid.NewString()
+	)
+
+	lock, err := l.Obtain(ctx, key, time.Second)
+	require.ErrorIsf(t, err, nil, "%s: unexpected obtain error: %v", t.Name(), err)
+	require.Equalf(t, key, lock.Key(), "%s: unexpected lock key", t.Name())
+
+	_, err = l.Obtain(ctx, key, time.Second)
+	require.ErrorIsf(t, err, locker.ErrLockNotObtained, "%s: unexpected obtain error: %v", t.Name(), err)
+
+	other, err := l.Obtain(ctx, uuid.NewString(), time.Second)
+	require.ErrorIsf(t, err, nil, "%s: unexpected obtain error: %v", t.Name(), err)
+
+	for _, lk := range []locker.Lock{lock, other} {
+		err = lk.Release(ctx)
+		require.ErrorIsf(t, err, nil, "%s: unexpected release error: %v", t.Name(), err)
+	}
+
+	lock, err = l.Obtain(ctx, key, time.Second)
+	require.ErrorIsf(t, err, nil, "%s: unexpected obtain error: %v", t.Name(), err)
+
+	err = lock.Release(ctx)
+	require.ErrorIsf(t, err, nil, "%s: unexpected release error: %v", t.Name(), err)
+}
+
+// testRelease check released lock can't be released or refreshed.
+func testRelease(t *testing.T, l locker.Locker) {
+
+	var (
+		ctx = context.Background()
+		key = uuid.NewString()
+	)
+
+	unlock, err := l.Lock(ctx, key, time.Second)
+	require.ErrorIsf(t, err, nil, "%s: unexpected lock error: %v", t.Name(), err)
+
+	err = unlock()
+	require.ErrorIsf(t, err, nil, "%s: unexpected unlock error: %v", t.Name(), err)
+
+	err = unlock()
+	require.ErrorIsf(t, err, locker.ErrNotLocked, "%s: unexpected unlock error: %v", t.Name(), err)
+
+	lock, err := l.Obtain(ctx, key, time.Second)
+	require.ErrorIsf(t, err, nil, "%s: unexpected obtain error: %v", t.Name(), err)
+
+	err = lock.Release(ctx)
+	require.ErrorIsf(t, err, nil, "%s: unexpected release error: %v", t.Name(), err)
+
+	err = lock.Refresh(ctx, time.Second)
+	require.ErrorIsf(t, err, locker.ErrNotLocked, "%s: unexpected refresh error: %v", t.Name(), err)
+
+	_, err = lock.TTL(ctx)
+	require.ErrorIsf(t, err, locker.ErrNotLocked, "%s: unexpected ttl error: %v", t.Name(), err)
+}
+
+// testExpire check lock is free after ttl.
+func testExpire(t *testing.T, l locker.Locker) {
+
+	var (
+		ctx = context.Background()
+		key = uuid.NewString()
+	)
+
+	lock, err := l.Obtain(ctx, key, 200*time.Millisecond)
+	require.ErrorIsf(t, err, nil, "%s: unexpected obtain error: %v", t.Name(), err)
+
+	time.Sleep(400 * time.Millisecond)
+
+	other, err := l.Obtain(ctx, key, time.Second)
+	require.ErrorIsf(t, err, nil, "%s: unexpected obtain error: %v", t.Name(), err)
+
+	err = lock.Refresh(ctx, time.Second)
+	require.ErrorIsf(t, err, locker.ErrNotLocked, "%s: unexpected refresh error: %v", t.Name(), err)
+
+	err = lock.Release(ctx)
+	require.ErrorIsf(t, err, locker.ErrNotLocked, "%s: unexpected release error: %v", t.Name(), err)
+
+	err = other.Release(ctx)
+	require.ErrorIsf(t, err, nil, "%s: unexpected release error: %v", t.Name(), err)
+}
+
+// testRefresh check refreshed lock is held longer than initial ttl.
+func testRefresh(t *testing.T, l locker.Locker) {
+
+	var (
+		ctx = context.Background()
+		key = uuid.NewString()
+		ttl = 400 * time.Millisecond
+	)
+
+	lock, err := l.Obtain(ctx, key, ttl)
+	require.ErrorIsf(t, err, nil, "%s: unexpected obtain error: %v", t.Name(), err)
+
+	time.Sleep(ttl / 2)
+
+	err = lock.Refresh(ctx, ttl)
+	require.ErrorIsf(t, err, nil, "%s: unexpected refresh error: %v", t.Name(), err)
+
+	left, err := lock.TTL(ctx)
+	require.ErrorIsf(t, err, nil, "%s: unexpected ttl error: %v", t.Name(), err)
+	require.Truef(t, left > ttl/2 && left <= ttl, "%s: unexpected ttl: %v", t.Name(), left)
+
+	time.Sleep(ttl * 3 / 4)
+
+	_, err = l.Obtain(ctx, key, ttl)
+	require.ErrorIsf(t, err, locker.ErrLockNotObtained, "%s: unexpected obtain error: %v", t.Name(), err)
+
+	err = lock.Release(ctx)
+	require.ErrorIsf(t, err, nil, "%s: unexpected release error: %v", t.Name(), err)
+}
+
+// testFencingToken check tokens grow with each obtain.
+func testFencingToken(t *testing.T, l locker.Locker) {
+
+	var (
+		ctx  = context.Background()
+		key  = uuid.NewString()
+		prev int64
+	)
+
+	for i := 0; i < 3; i++ {
+		lock, err := l.Obtain(ctx, key, time.Second)
+		require.ErrorIsf(t, err, nil, "%s: unexpected obtain error: %v", t.Name(), err)
+		require.Greaterf(t, lock.Token(), prev, "%s: token is not increased", t.Name())
+		prev = lock.Token()
+
+		err = lock.Release(ctx)
+		require.ErrorIsf(t, err, nil, "%s: unexpected release error: %v", t.Name(), err)
+	}
+}
+
+// testAutoRefresh check lock is kept by auto refresh.
+func testAutoRefresh(t *testing.T, l locker.Locker) {
+
+	var (
+		ctx = context.Background()
+		key = uuid.NewString()
+		ttl = 300 * time.Millisecond
+	)
+
+	lock, err := l.Obtain(ctx, key, ttl)
+	require.ErrorIsf(t, err, nil, "%s: unexpected obtain error: %v", t.Name(), err)
+
+	rCtx, cancel := locker.AutoRefresh(ctx, lock, ttl)
+
+	time.Sleep(3 * ttl)
+
+	_, err = l.Obtain(ctx, key, ttl)
+	require.ErrorIsf(t, err, locker.ErrLockNotObtained, "%s: unexpected obtain error: %v", t.Name(), err)
+	require.ErrorIsf(t, rCtx.Err(), nil, "%s: lock is lost", t.Name())
+
+	cancel()
+
+	err = lock.Release(ctx)
+	require.ErrorIsf(t, err, nil, "%s: unexpected release error: %v", t.Name(), err)
+}
+
+// testInvalidTTL check sub millisecond ttl is rejected.
+func testInvalidTTL(t *testing.T, l locker.Locker) {
+
+	var (
+		ctx = context.Background()
+		key = uuid.NewString()
+	)
+
+	for _, ttl := range []time.Duration{0, time.Microsecond} {
+		_, err := l.Obtain(ctx, key, ttl)
+		require.Errorf(t, err, "%s: invalid ttl %v is accepted", t.Name(), ttl)
+	}
+
+	lock, err := l.Obtain(ctx, key, time.Second)
+	require.ErrorIsf(t, err, nil, "%s: unexpected obtain error: %v", t.Name(), err)
+
+	rCtx, cancel := locker.AutoRefresh(ctx, lock, time.Nanosecond)
+	defer cancel()
+	require.Errorf(t, rCtx.Err(), "%s: auto refresh with invalid ttl is not cancelled", t.Name())
+
+	err = lock.Release(ctx)
+	require.ErrorIsf(t, err, nil, "%s: unexpected release error: %v", t.Name(), err)
+}
@@ -0,0 +1,42 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/locker"
+	"github.com/tarusov/rig/locker/memory"
+)
+
+func TestDo(t *testing.T) {
+
+	var (
+		l     = memory.New(memory.WithRetryCount(0))
+		ctx   = context.Background()
+		key   = uuid.NewString()
+		errFn = errors.New("fn error")
+	)
+
+	err := locker.Do(ctx, l, key, time.Second, func(ctx context.Context) error {
+		_, err := l.Obtain(ctx, key, time.Second)
+		require.ErrorIsf(t, err, locker.ErrLockNotObtained, "TestDo: lock is not held: %v", err)
+		return errFn
+	})
+	require.ErrorIsf(t, err, errFn, "TestDo: unexpected do error: %v", err)
+
+	require.Panicsf(t, func() {
+		_ = locker.Do(ctx, l, key, time.Second, func(context.Context) error {
+			panic("fn panic")
+		})
+	}, "TestDo: panic is not propagated")
+
+	// Lock is released after panic.
+	err = locker.Do(ctx, l, key, time.Second, func(context.Context) error {
+		return nil
+	})
+	require.ErrorIsf(t, err, nil, "TestDo: unexpected do error: %v", err)
+}
@@ -0,0 +1,74 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/locker"
+	"github.com/tarusov/rig/locker/memory"
+	"github.com/tarusov/rig/metrics"
+)
+
+func TestInstrument(t *testing.T) {
+
+	var (
+		registry = metrics.New()
+		tracer   = mocktracer.New()
+		ctx      = context.Background()
+	)
+
+	l, err := locker.Instrument(memory.New(memory.WithRetryCount(0)),
+		locker.WithMetrics(registry),
+		locker.WithTracer(tracer),
+	)
+	require.ErrorIsf(t, err, nil, "TestInstrument: unexpected instrument error: %v", err)
+
+	unlock, err := l.Lock(ctx, "jobs:"+uuid.NewString(), time.Second)
+	require.ErrorIsf(t, err, nil, "TestInstrument: unexpected lock error: %v", err)
+
+	lock, err := l.Obtain(ctx, "jobs:"+uuid.NewString(), time.Second)
+	require.ErrorIsf(t, err, nil, "TestInstrument: unexpected obtain error: %v", err)
+
+	_, err = l.Obtain(ctx, lock.Key(), time.Second)
+	require.ErrorIsf(t, err, locker.ErrLockNotObtained, "TestInstrument: unexpected obtain error: %v", err)
+
+	err = unlock()
+	require.ErrorIsf(t, err, nil, "TestInstrument: unexpected unlock error: %v", err)
+
+	err = lock.Release(ctx)
+	require.ErrorIsf(t, err, nil, "TestInstrument: unexpected release error: %v", err)
+
+	require.Lenf(t, tracer.FinishedSpans(), 5, "TestInstrument: unexpected spans count")
+
+	families, err := registry.Gather()
+	require.ErrorIsf(t, err, nil, "TestInstrument: unexpected gather error: %v", err)
+
+	var obtains = make(map[string]float64)
+	var holds uint64
+	for _, mf := range families {
+		for _, m := range mf.GetMetric() {
+			var labels = make(map[string]string)
+			for _, lp := range m.GetLabel() {
+				labels[lp.GetName()] = lp.GetValue()
+			}
+			if labels["prefix"] != "jobs" {
+				continue
+			}
+
+			switch mf.GetName() {
+			case "locker_obtains_total":
+				obtains[labels["result"]] = m.GetCounter().GetValue()
+			case "locker_hold_seconds":
+				holds = m.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+
+	require.Equalf(t, float64(2), obtains["obtained"], "TestInstrument: unexpected obtained count")
+	require.Equalf(t, float64(1), obtains["not_obtained"], "TestInstrument: unexpected not obtained count")
+	require.Equalf(t, uint64(2), holds, "TestInstrument: unexpected hold count")
+}
@@ -0,0 +1,195 @@
+// Package memory contains in-process locker driver. It is useful for tests
+// and single instance services.
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tarusov/rig/locker"
+)
+
+type (
+	// Locker is in-process locks driver.
+	Locker struct {
+		mu     sync.Mutex
+		locks  map[string]*entry
+		fences map[string]int64
+		seq    uint64
+
+		retryCount   int
+		retryTimeout time.Duration
+	}
+
+	// entry is held lock state.
+	entry struct {
+		id      uint64
+		expires time.Time
+	}
+
+	// lock is obtained lock handle.
+	lock struct {
+		l     *Locker
+		key   string
+		id    uint64
+		token int64
+	}
+)
+
+// Defaults.
+const (
+	defaultRetryCount   = 3
+	defaultRetryTimeout = 3 * time.Second
+)
+
+// New creates new memory locker instance.
+func New(opts ...lockerOption) *Locker {
+
+	var l = &Locker{
+		locks:        make(map[string]*entry),
+		fences:       make(map[string]int64),
+		retryCount:   defaultRetryCount,
+		retryTimeout: defaultRetryTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// Lock method create new mutex lock. Return unlock func or error.
+func (l *Locker) Lock(ctx context.Context, key string, ttl time.Duration) (locker.UnlockFunc, error) {
+
+	obtained, err := l.Obtain(ctx, key, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		return obtained.Release(ctx)
+	}, nil
+}
+
+// Obtain method create new mutex lock. Return lock handle or error.
+func (l *Locker) Obtain(ctx context.Context, key string, ttl time.Duration) (locker.Lock, error) {
+
+	if err := locker.CheckTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	var obtained *lock
+
+	err := locker.Retry(ctx, l.retryCount, l.retryTimeout, func() (bool, error) {
+		obtained = l.try(key, ttl)
+		return obtained != nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return obtained, nil
+}
+
+// try obtain lock if it is free or expired.
+func (l *Locker) try(key string, ttl time.Duration) *lock {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.held(key, 0); ok {
+		return nil
+	}
+
+	l.seq++
+	l.fences[key]++
+	l.locks[key] = &entry{
+		id:      l.seq,
+		expires: time.Now().Add(ttl),
+	}
+
+	return &lock{
+		l:     l,
+		key:   key,
+		id:    l.seq,
+		token: l.fences[key],
+	}
+}
+
+// held return not expired lock entry of key. If id is not 0, entry should
+// have same id. Expired entry is removed. Must be called under mutex.
+func (l *Locker) held(key string, id uint64) (*entry, bool) {
+
+	e, ok := l.locks[key]
+	if !ok {
+		return nil, false
+	}
+
+	if !time.Now().Before(e.expires) {
+		delete(l.locks, key)
+		return nil, false
+	}
+
+	if id != 0 && e.id != id {
+		return nil, false
+	}
+
+	return e, true
+}
+
+// Key return locked key.
+func (lk *lock) Key() string {
+	return lk.key
+}
+
+// Token return lock fencing token.
+func (lk *lock) Token() int64 {
+	return lk.token
+}
+
+// Refresh method extend lock ttl. Return ErrNotLocked if lock is lost.
+func (lk *lock) Refresh(_ context.Context, ttl time.Duration) error {
+
+	lk.l.mu.Lock()
+	defer lk.l.mu.Unlock()
+
+	e, ok := lk.l.held(lk.key, lk.id)
+	if !ok {
+		return locker.ErrNotLocked
+	}
+
+	e.expires = time.Now().Add(ttl)
+
+	return nil
+}
+
+// TTL return remaining lock ttl. Return ErrNotLocked if lock is lost.
+func (lk *lock) TTL(_ context.Context) (time.Duration, error) {
+
+	lk.l.mu.Lock()
+	defer lk.l.mu.Unlock()
+
+	e, ok := lk.l.held(lk.key, lk.id)
+	if !ok {
+		return 0, locker.ErrNotLocked
+	}
+
+	return time.Until(e.expires), nil
+}
+
+// Release method try to release current lock.
+func (lk *lock) Release(_ context.Context) error {
+
+	lk.l.mu.Lock()
+	defer lk.l.mu.Unlock()
+
+	if _, ok := lk.l.held(lk.key, lk.id); !ok {
+		return locker.ErrNotLocked
+	}
+
+	delete(lk.l.locks, lk.key)
+
+	return nil
+}
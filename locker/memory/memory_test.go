@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/tarusov/rig/locker"
+	"github.com/tarusov/rig/locker/lockertest"
+	"github.com/tarusov/rig/locker/memory"
+)
+
+func TestConformance(t *testing.T) {
+	lockertest.Run(t, func(t *testing.T) locker.Locker {
+		return memory.New(memory.WithRetryCount(0))
+	})
+}
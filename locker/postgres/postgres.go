@@ -0,0 +1,275 @@
+// Package postgres contains PostgreSQL advisory locks locker driver.
+//
+// Each held lock pins one database connection, so lock is released by
+// PostgreSQL if holder connection is closed. Lock ttl is enforced by holder:
+// lock is released if it is not refreshed during ttl. Fencing tokens are
+// stored in table, which is created by New.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/tarusov/rig/locker"
+	"github.com/tarusov/rig/logger"
+)
+
+type (
+	// Locker is postgres advisory locks driver.
+	Locker struct {
+		db           *sql.DB
+		table        string
+		retryCount   int
+		retryTimeout time.Duration
+	}
+
+	// lock is obtained lock handle.
+	lock struct {
+		mu       sync.Mutex
+		conn     *sql.Conn
+		timer    *time.Timer
+		expires  time.Time
+		released bool
+
+		key   string
+		id    int64
+		token int64
+		ctx   context.Context
+	}
+)
+
+// Defaults.
+const (
+	defaultTable        = "locker_fences"
+	defaultRetryCount   = 3
+	defaultRetryTimeout = 3 * time.Second
+)
+
+// New creates new postgres locker instance and fencing tokens table.
+func New(ctx context.Context, db *sql.DB, opts ...lockerOption) (*Locker, error) {
+
+	var l = &Locker{
+		db:           db,
+		table:        defaultTable,
+		retryCount:   defaultRetryCount,
+		retryTimeout: defaultRetryTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	var query = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		key   TEXT PRIMARY KEY,
+		token BIGINT NOT NULL
+	)`, l.table)
+
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return nil, fmt.Errorf("failed to create fences table: %w", err)
+	}
+
+	return l, nil
+}
+
+// Lock method create new advisory lock. Return unlock func or error.
+func (l *Locker) Lock(ctx context.Context, key string, ttl time.Duration) (locker.UnlockFunc, error) {
+
+	obtained, err := l.Obtain(ctx, key, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		return obtained.Release(ctx)
+	}, nil
+}
+
+// Obtain method create new advisory lock. Return lock handle or error.
+func (l *Locker) Obtain(ctx context.Context, key string, ttl time.Duration) (locker.Lock, error) {
+
+	if err := locker.CheckTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	var id = lockID(key)
+
+	err = locker.Retry(ctx, l.retryCount, l.retryTimeout, func() (bool, error) {
+		var ok bool
+		err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", id).Scan(&ok)
+		return ok, err
+	})
+	if err != nil {
+		if !errors.Is(err, locker.ErrLockNotObtained) {
+			discard(conn)
+		}
+		_ = conn.Close()
+		return nil, err
+	}
+
+	var (
+		token int64
+		query = fmt.Sprintf(`INSERT INTO %[1]s (key, token) VALUES ($1, 1)
+			ON CONFLICT (key) DO UPDATE SET token = %[1]s.token + 1
+			RETURNING token`, l.table)
+	)
+
+	if err = conn.QueryRowContext(ctx, query, key).Scan(&token); err != nil {
+		_ = unlock(ctx, conn, id)
+		return nil, fmt.Errorf("failed to issue fencing token: %w", err)
+	}
+
+	logger.FromContext(ctx).WithField("key", key).WithField("token", token).Debug("lock obtained")
+
+	var lk = &lock{
+		conn:    conn,
+		expires: time.Now().Add(ttl),
+		key:     key,
+		id:      id,
+		token:   token,
+		ctx:     ctx,
+	}
+	lk.timer = time.AfterFunc(ttl, lk.expire)
+
+	return lk, nil
+}
+
+// Key return locked key.
+func (lk *lock) Key() string {
+	return lk.key
+}
+
+// Token return lock fencing token.
+func (lk *lock) Token() int64 {
+	return lk.token
+}
+
+// Refresh method extend lock ttl. Return ErrNotLocked if lock is lost.
+func (lk *lock) Refresh(ctx context.Context, ttl time.Duration) error {
+
+	lk.mu.Lock()
+	defer lk.mu.Unlock()
+
+	if lk.released {
+		return locker.ErrNotLocked
+	}
+
+	// Lock is lost if connection was broken.
+	var held bool
+	err := lk.conn.QueryRowContext(ctx, `SELECT EXISTS (
+		SELECT 1 FROM pg_locks
+		WHERE locktype = 'advisory' AND pid = pg_backend_pid() AND granted
+			AND classid = $1 AND objid = $2 AND objsubid = 1
+	)`, int64(uint32(lk.id>>32)), int64(uint32(lk.id))).Scan(&held)
+	if err != nil {
+		return err
+	}
+	if !held {
+		_ = lk.close(ctx)
+		return locker.ErrNotLocked
+	}
+
+	lk.expires = time.Now().Add(ttl)
+	lk.timer.Reset(ttl)
+
+	return nil
+}
+
+// TTL return remaining lock ttl. Return ErrNotLocked if lock is lost.
+func (lk *lock) TTL(_ context.Context) (time.Duration, error) {
+
+	lk.mu.Lock()
+	defer lk.mu.Unlock()
+
+	if lk.released {
+		return 0, locker.ErrNotLocked
+	}
+
+	return time.Until(lk.expires), nil
+}
+
+// Release method try to release current lock.
+func (lk *lock) Release(ctx context.Context) (err error) {
+	defer func() {
+		logger.FromContext(lk.ctx).WithField("key", lk.key).WithErr(err).Debug("unlocked")
+	}()
+
+	lk.mu.Lock()
+	defer lk.mu.Unlock()
+
+	if lk.released {
+		return locker.ErrNotLocked
+	}
+
+	return lk.close(ctx)
+}
+
+// expire release lock if it is not refreshed.
+func (lk *lock) expire() {
+
+	lk.mu.Lock()
+	defer lk.mu.Unlock()
+
+	if lk.released || time.Now().Before(lk.expires) {
+		return
+	}
+
+	if err := lk.close(context.Background()); err != nil {
+		logger.FromContext(lk.ctx).WithField("key", lk.key).WithErr(err).Warn("failed to release expired lock")
+	}
+}
+
+// close unlock advisory lock and return connection to pool.
+// Must be called under mutex.
+func (lk *lock) close(ctx context.Context) error {
+
+	lk.released = true
+	lk.timer.Stop()
+
+	return unlock(ctx, lk.conn, lk.id)
+}
+
+// unlock release advisory lock and close connection.
+func unlock(ctx context.Context, conn *sql.Conn, id int64) error {
+
+	defer conn.Close()
+
+	var ok bool
+	err := conn.QueryRowContext(ctx, "SELECT pg_advisory_unlock($1)", id).Scan(&ok)
+	if err != nil {
+		discard(conn)
+		return err
+	}
+	if !ok {
+		return locker.ErrNotLocked
+	}
+
+	return nil
+}
+
+// discard remove connection from pool, so its session locks are released
+// by server.
+func discard(conn *sql.Conn) {
+	_ = conn.Raw(func(interface{}) error {
+		return driver.ErrBadConn
+	})
+}
+
+// lockID return advisory lock id of key.
+func lockID(key string) int64 {
+
+	var h = fnv.New64a()
+	_, _ = h.Write([]byte(key))
+
+	return int64(h.Sum64())
+}
@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"time"
+)
+
+// lockerOption is locker constructor optional modificator.
+type lockerOption func(*Locker)
+
+// WithRetryCount set custom retry count.
+func WithRetryCount(n int) lockerOption {
+	return func(l *Locker) {
+		l.retryCount = n
+	}
+}
+
+// WithRetryTimeout set custom retry timeout.
+func WithRetryTimeout(t time.Duration) lockerOption {
+	return func(l *Locker) {
+		l.retryTimeout = t
+	}
+}
+
+// WithFencesTable set custom fencing tokens table name ("locker_fences" by
+// default). Name is not escaped.
+func WithFencesTable(table string) lockerOption {
+	return func(l *Locker) {
+		l.table = table
+	}
+}
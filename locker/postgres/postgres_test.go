@@ -0,0 +1,49 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"os"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/internal/testcontainer"
+	"github.com/tarusov/rig/locker"
+	"github.com/tarusov/rig/locker/lockertest"
+	"github.com/tarusov/rig/locker/postgres"
+)
+
+// postgresURI for tests. External server may be set with POSTGRES_URI env.
+var postgresURI string
+
+func TestMain(m *testing.M) {
+
+	uri, terminate, err := testcontainer.Postgres(context.Background())
+	if err != nil {
+		log.Println("postgres container not inited", err)
+	}
+	postgresURI = uri
+
+	var code = m.Run()
+	terminate()
+	os.Exit(code)
+}
+
+func TestConformance(t *testing.T) {
+
+	if postgresURI == "" {
+		t.Skip("server not inited")
+	}
+
+	db, err := sql.Open("postgres", postgresURI)
+	require.ErrorIsf(t, err, nil, "TestConformance: unexpected open error: %v", err)
+	defer db.Close()
+
+	lockertest.Run(t, func(t *testing.T) locker.Locker {
+		l, err := postgres.New(context.Background(), db, postgres.WithRetryCount(0))
+		require.ErrorIsf(t, err, nil, "TestConformance: unexpected locker error: %v", err)
+		return l
+	})
+}
@@ -0,0 +1,187 @@
+package locker
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/tarusov/rig/logger"
+)
+
+type (
+	// RedisLocker is redis locks driver.
+	RedisLocker struct {
+		client       redis.UniversalClient
+		retryCount   int
+		retryTimeout time.Duration
+	}
+
+	// redisLock is obtained lock handle.
+	redisLock struct {
+		client  redis.UniversalClient
+		scripts *handleScripts
+		key     string
+		target  string
+		value   string
+		token   int64
+		ctx     context.Context
+	}
+)
+
+// Defaults.
+const (
+	defaultRetryCount   = 3
+	defaultRetryTimeout = 3 * time.Second
+	defaultDriftFactor  = 0.01
+)
+
+// New creates new redis locker instance. It returned *Locker before Locker
+// became an interface, see package doc.
+func New(client redis.UniversalClient, opts ...lockerOption) (*RedisLocker, error) {
+
+	var lo = newLockerOptions(opts)
+
+	return &RedisLocker{
+		client:       client,
+		retryCount:   lo.retryCount,
+		retryTimeout: lo.retryTimeout,
+	}, nil
+}
+
+// newLockerOptions apply options to defaults.
+func newLockerOptions(opts []lockerOption) *lockerOptions {
+
+	var lo = &lockerOptions{
+		retryCount:   defaultRetryCount,
+		retryTimeout: defaultRetryTimeout,
+		driftFactor:  defaultDriftFactor,
+	}
+
+	for _, opt := range opts {
+		opt(lo)
+	}
+
+	return lo
+}
+
+// Lock method create new redis mutex lock. Return unlock func or error.
+func (l *RedisLocker) Lock(ctx context.Context, key string, ttl time.Duration) (UnlockFunc, error) {
+
+	obtained, err := l.Obtain(ctx, key, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		return obtained.Release(ctx)
+	}, nil
+}
+
+// Obtain method create new redis mutex lock. Return lock handle or error.
+// Each obtained lock gets fencing token, greater than tokens of all previous
+// locks of the key.
+func (l *RedisLocker) Obtain(ctx context.Context, key string, ttl time.Duration) (Lock, error) {
+
+	if err := CheckTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	value, err := ownerValue()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := l.acquire(ctx, scriptObtain, []string{key, subKey(key, "fence")}, value, ttl.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).WithField("key", key).WithField("token", token).Debug("lock obtained")
+
+	return l.newLock(ctx, stringScripts, key, key, value, token), nil
+}
+
+// acquire run obtain script until it returns positive result or retries
+// are exhausted.
+func (l *RedisLocker) acquire(ctx context.Context, script *redis.Script, keys []string, args ...interface{}) (int64, error) {
+
+	var res int64
+
+	err := Retry(ctx, l.retryCount, l.retryTimeout, func() (bool, error) {
+		var err error
+		res, err = script.Run(ctx, l.client, keys, args...).Int64()
+		return res > 0, err
+	})
+
+	return res, err
+}
+
+// newLock creates lock handle.
+func (l *RedisLocker) newLock(ctx context.Context, scripts *handleScripts, key, target, value string, token int64) *redisLock {
+	return &redisLock{
+		client:  l.client,
+		scripts: scripts,
+		key:     key,
+		target:  target,
+		value:   value,
+		token:   token,
+		ctx:     ctx,
+	}
+}
+
+// Key return locked key.
+func (l *redisLock) Key() string {
+	return l.key
+}
+
+// Token return lock fencing token. Tokens grow monotonically with each lock
+// obtain, so storages may reject writes with token less than already seen.
+// Shared locks (readers, semaphores) have no fencing token.
+func (l *redisLock) Token() int64 {
+	return l.token
+}
+
+// Refresh method extend lock ttl. Return ErrNotLocked if lock is lost.
+func (l *redisLock) Refresh(ctx context.Context, ttl time.Duration) error {
+
+	res, err := l.scripts.refresh.Run(ctx, l.client, []string{l.target}, l.value, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+	if res != 1 {
+		return ErrNotLocked
+	}
+
+	return nil
+}
+
+// TTL return remaining lock ttl. Return ErrNotLocked if lock is lost.
+func (l *redisLock) TTL(ctx context.Context) (time.Duration, error) {
+
+	ms, err := l.scripts.ttl.Run(ctx, l.client, []string{l.target}, l.value).Int64()
+	if err != nil {
+		return 0, err
+	}
+	if ms < 0 {
+		return 0, ErrNotLocked
+	}
+
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// Release method try to release current lock.
+func (l *redisLock) Release(ctx context.Context) (err error) {
+	defer func() {
+		logger.FromContext(l.ctx).WithField("key", l.key).WithErr(err).Debug("unlocked")
+	}()
+
+	res, err := l.scripts.release.Run(ctx, l.client, []string{l.target}, l.value, subKey(l.key, "released")).Int64()
+	if err != nil {
+		return err
+	}
+	if res != 1 {
+		return ErrNotLocked
+	}
+
+	return nil
+}
@@ -0,0 +1,283 @@
+package locker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/tarusov/rig/logger"
+)
+
+type (
+	// Redlock is redis locks driver, which obtains locks on quorum of
+	// independent redis nodes, so single node failover can't grant lock twice.
+	Redlock struct {
+		clients      []redis.UniversalClient
+		quorum       int
+		retryCount   int
+		retryTimeout time.Duration
+		driftFactor  float64
+	}
+
+	// redlockLock is obtained redlock handle.
+	redlockLock struct {
+		r     *Redlock
+		key   string
+		value string
+		token int64
+		ctx   context.Context
+	}
+
+	// nodeResult is script result of single node.
+	nodeResult struct {
+		res int64
+		err error
+	}
+)
+
+// redlockDrift is added to clock drift to account redis expiration precision.
+const redlockDrift = 2 * time.Millisecond
+
+// NewRedlock creates new redlock instance. Clients should be connected to
+// independent redis nodes (not replicas of one master).
+func NewRedlock(clients []redis.UniversalClient, opts ...lockerOption) (*Redlock, error) {
+
+	if len(clients) == 0 {
+		return nil, errors.New("redis clients are not defined")
+	}
+
+	var lo = newLockerOptions(opts)
+
+	if lo.driftFactor < 0 || lo.driftFactor >= 1 {
+		return nil, fmt.Errorf("invalid drift factor: %v", lo.driftFactor)
+	}
+
+	return &Redlock{
+		clients:      clients,
+		quorum:       len(clients)/2 + 1,
+		retryCount:   lo.retryCount,
+		retryTimeout: lo.retryTimeout,
+		driftFactor:  lo.driftFactor,
+	}, nil
+}
+
+// Lock method create new redlock mutex lock. Return unlock func or error.
+func (r *Redlock) Lock(ctx context.Context, key string, ttl time.Duration) (UnlockFunc, error) {
+
+	obtained, err := r.Obtain(ctx, key, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		return obtained.Release(ctx)
+	}, nil
+}
+
+// Obtain method create new redlock mutex lock. Lock is obtained if it is set
+// on quorum of nodes within validity time: ttl reduced by elapsed time and
+// clock drift. Otherwise it is released on all nodes and obtain is retried
+// after retry timeout with random jitter.
+// Fencing token is maximum of quorum nodes tokens, it is raised on all nodes,
+// so any next quorum gets greater token.
+func (r *Redlock) Obtain(ctx context.Context, key string, ttl time.Duration) (Lock, error) {
+
+	if err := CheckTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	value, err := ownerValue()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		fence = subKey(key, "fence")
+		token int64
+	)
+
+	err = retry(ctx, r.retryCount, r.retryDelay, func() (bool, error) {
+		var (
+			start   = time.Now()
+			results = r.run(ctx, scriptObtain, []string{key, fence}, value, ttl.Milliseconds())
+		)
+
+		token = 0
+		for _, nr := range results {
+			if nr.err == nil && nr.res > token {
+				token = nr.res
+			}
+		}
+
+		n, err := r.count(results, func(res int64) bool { return res > 0 })
+		if err == nil && n >= r.quorum && r.valid(start, ttl) {
+			return true, nil
+		}
+
+		r.run(ctx, scriptRelease, []string{key}, value)
+
+		return false, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.run(ctx, scriptFenceSync, []string{fence}, token)
+
+	logger.FromContext(ctx).WithField("key", key).WithField("token", token).Debug("redlock obtained")
+
+	return &redlockLock{
+		r:     r,
+		key:   key,
+		value: value,
+		token: token,
+		ctx:   ctx,
+	}, nil
+}
+
+// retryDelay return retry timeout with random jitter in [timeout/2, timeout*3/2),
+// so concurrent clients don't retry in lockstep and split votes again.
+func (r *Redlock) retryDelay() time.Duration {
+
+	if r.retryTimeout <= 0 {
+		return 0
+	}
+
+	return r.retryTimeout/2 + time.Duration(rand.Int63n(int64(r.retryTimeout)))
+}
+
+// run script on all nodes concurrently.
+func (r *Redlock) run(ctx context.Context, script *redis.Script, keys []string, args ...interface{}) []nodeResult {
+
+	var (
+		results = make([]nodeResult, len(r.clients))
+		wg      sync.WaitGroup
+	)
+
+	for i, client := range r.clients {
+		wg.Add(1)
+		go func(i int, client redis.UniversalClient) {
+			defer wg.Done()
+			results[i].res, results[i].err = script.Run(ctx, client, keys, args...).Int64()
+		}(i, client)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// count return count of successful node results. Return error if all nodes failed.
+func (r *Redlock) count(results []nodeResult, ok func(res int64) bool) (int, error) {
+
+	var (
+		n    int
+		errs int
+		err  error
+	)
+
+	for _, nr := range results {
+		switch {
+		case nr.err != nil:
+			errs++
+			err = nr.err
+		case ok(nr.res):
+			n++
+		}
+	}
+
+	if errs == len(results) {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// valid check lock validity time is not exceeded.
+func (r *Redlock) valid(start time.Time, ttl time.Duration) bool {
+
+	var drift = time.Duration(float64(ttl)*r.driftFactor) + redlockDrift
+
+	return time.Since(start)+drift < ttl
+}
+
+// Key return locked key.
+func (l *redlockLock) Key() string {
+	return l.key
+}
+
+// Token return lock fencing token.
+func (l *redlockLock) Token() int64 {
+	return l.token
+}
+
+// Refresh method extend lock ttl on all nodes. Return ErrNotLocked if lock
+// is not held by quorum within validity time.
+func (l *redlockLock) Refresh(ctx context.Context, ttl time.Duration) error {
+
+	var (
+		start   = time.Now()
+		results = l.r.run(ctx, scriptRefresh, []string{l.key}, l.value, ttl.Milliseconds())
+	)
+
+	n, err := l.r.count(results, func(res int64) bool { return res == 1 })
+	if err != nil {
+		return err
+	}
+	if n < l.r.quorum || !l.r.valid(start, ttl) {
+		return ErrNotLocked
+	}
+
+	return nil
+}
+
+// TTL return remaining lock ttl on quorum of nodes. Return ErrNotLocked if
+// lock is not held by quorum.
+func (l *redlockLock) TTL(ctx context.Context) (time.Duration, error) {
+
+	var results = l.r.run(ctx, scriptTTL, []string{l.key}, l.value)
+
+	if _, err := l.r.count(results, func(int64) bool { return true }); err != nil {
+		return 0, err
+	}
+
+	var ttls []int64
+	for _, nr := range results {
+		if nr.err == nil && nr.res >= 0 {
+			ttls = append(ttls, nr.res)
+		}
+	}
+
+	if len(ttls) < l.r.quorum {
+		return 0, ErrNotLocked
+	}
+
+	sort.Slice(ttls, func(i, j int) bool { return ttls[i] > ttls[j] })
+
+	return time.Duration(ttls[l.r.quorum-1]) * time.Millisecond, nil
+}
+
+// Release method release lock on all nodes. Return ErrNotLocked if lock was
+// not held by quorum.
+func (l *redlockLock) Release(ctx context.Context) (err error) {
+	defer func() {
+		logger.FromContext(l.ctx).WithField("key", l.key).WithErr(err).Debug("unlocked")
+	}()
+
+	var results = l.r.run(ctx, scriptRelease, []string{l.key}, l.value)
+
+	n, err := l.r.count(results, func(res int64) bool { return res == 1 })
+	if err != nil {
+		return err
+	}
+	if n < l.r.quorum {
+		return ErrNotLocked
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package locker
+
+import (
+	"context"
+	"time"
+
+	"github.com/tarusov/rig/logger"
+)
+
+// ObtainRead method create shared read lock of key. Many readers may hold
+// the lock at once, while no writer holds it. Return lock handle or error.
+func (l *RedisLocker) ObtainRead(ctx context.Context, key string, ttl time.Duration) (Lock, error) {
+
+	if err := CheckTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	value, err := ownerValue()
+	if err != nil {
+		return nil, err
+	}
+
+	var readers = subKey(key, "readers")
+
+	_, err = l.acquire(ctx, scriptObtainRead, []string{key, readers}, value, ttl.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).WithField("key", key).Debug("read lock obtained")
+
+	return l.newLock(ctx, memberScripts, key, readers, value, 0), nil
+}
+
+// ObtainWrite method create exclusive write lock of key. It is obtained only
+// when there are no readers, and has fencing token like mutex lock.
+// Return lock handle or error.
+func (l *RedisLocker) ObtainWrite(ctx context.Context, key string, ttl time.Duration) (Lock, error) {
+
+	if err := CheckTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	value, err := ownerValue()
+	if err != nil {
+		return nil, err
+	}
+
+	var keys = []string{key, subKey(key, "readers"), subKey(key, "fence")}
+
+	token, err := l.acquire(ctx, scriptObtainWrite, keys, value, ttl.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).WithField("key", key).WithField("token", token).Debug("write lock obtained")
+
+	return l.newLock(ctx, stringScripts, key, key, value, token), nil
+}
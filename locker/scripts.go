@@ -0,0 +1,239 @@
+package locker
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type (
+	// handleScripts is set of lock handle scripts.
+	handleScripts struct {
+		refresh *redis.Script
+		ttl     *redis.Script
+		release *redis.Script
+	}
+)
+
+// luaNow is scripts prelude, it sets now to redis server time in ms.
+const luaNow = `
+local t = redis.call("time")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+`
+
+// Exclusive lock scripts. Lock key holds random owner value, fence key holds
+// fencing tokens counter and never expires.
+var (
+	// KEYS: lock, fence. ARGV: value, ttl ms. Return fencing token or 0.
+	scriptObtain = redis.NewScript(`
+if redis.call("set", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("incr", KEYS[2])
+end
+return 0
+`)
+
+	// KEYS: lock. ARGV: value, ttl ms. Return 1 if refreshed.
+	scriptRefresh = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+	// KEYS: lock. ARGV: value. Return ttl ms or -3 if lock is not held.
+	scriptTTL = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pttl", KEYS[1])
+end
+return -3
+`)
+
+	// KEYS: lock. ARGV: value, optional release channel. Return 1 if released.
+	scriptRelease = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	redis.call("del", KEYS[1])
+	if ARGV[2] then
+		redis.call("publish", ARGV[2], "released")
+	end
+	return 1
+end
+return 0
+`)
+
+	// KEYS: fence. ARGV: token. Raise fencing tokens counter up to token.
+	scriptFenceSync = redis.NewScript(`
+if tonumber(redis.call("get", KEYS[1]) or "0") < tonumber(ARGV[1]) then
+	redis.call("set", KEYS[1], ARGV[1])
+end
+return 1
+`)
+
+	// KEYS: lock, fence, queue, timeouts. ARGV: value, ttl ms, waiter ttl ms.
+	// Lock is obtained if it is free and waiter is queue head, otherwise
+	// waiter is queued. Timed out waiters are removed from queue head.
+	// Return fencing token or negative ms to wait before next try.
+	scriptObtainWait = redis.NewScript(luaNow + `
+local wttl = tonumber(ARGV[3])
+while true do
+	local head = redis.call("lindex", KEYS[3], 0)
+	if not head then
+		break
+	end
+	local exp = redis.call("zscore", KEYS[4], head)
+	if exp and tonumber(exp) > now then
+		break
+	end
+	redis.call("lpop", KEYS[3])
+	redis.call("zrem", KEYS[4], head)
+end
+local head = redis.call("lindex", KEYS[3], 0)
+if redis.call("exists", KEYS[1]) == 0 and (not head or head == ARGV[1]) then
+	redis.call("set", KEYS[1], ARGV[1], "PX", ARGV[2])
+	if head then
+		redis.call("lpop", KEYS[3])
+		redis.call("zrem", KEYS[4], ARGV[1])
+	end
+	return redis.call("incr", KEYS[2])
+end
+if not redis.call("zscore", KEYS[4], ARGV[1]) then
+	redis.call("rpush", KEYS[3], ARGV[1])
+	head = head or ARGV[1]
+end
+redis.call("zadd", KEYS[4], now + wttl, ARGV[1])
+redis.call("pexpire", KEYS[3], wttl)
+redis.call("pexpire", KEYS[4], wttl)
+local wait = redis.call("pttl", KEYS[1])
+if wait < 0 then
+	wait = tonumber(redis.call("zscore", KEYS[4], head)) - now
+end
+if wait < 1 then
+	wait = 1
+end
+return -wait
+`)
+
+	// KEYS: queue, timeouts. ARGV: value, release channel. Remove waiter
+	// from queue and wake up other waiters.
+	scriptDequeue = redis.NewScript(`
+redis.call("lrem", KEYS[1], 0, ARGV[1])
+redis.call("zrem", KEYS[2], ARGV[1])
+redis.call("publish", ARGV[2], "dequeued")
+return 1
+`)
+
+	stringScripts = &handleScripts{
+		refresh: scriptRefresh,
+		ttl:     scriptTTL,
+		release: scriptRelease,
+	}
+)
+
+// Shared lock scripts. Holders are sorted set members with expiration time
+// as score, expired members are removed on obtain.
+var (
+	// KEYS: lock, readers, fence. ARGV: value, ttl ms. Return fencing token or 0.
+	scriptObtainWrite = redis.NewScript(luaNow + `
+redis.call("zremrangebyscore", KEYS[2], "-inf", now)
+if redis.call("zcard", KEYS[2]) > 0 then
+	return 0
+end
+if redis.call("set", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("incr", KEYS[3])
+end
+return 0
+`)
+
+	// KEYS: lock, readers. ARGV: value, ttl ms. Return 1 if obtained.
+	scriptObtainRead = redis.NewScript(luaNow + `
+if redis.call("exists", KEYS[1]) == 1 then
+	return 0
+end
+local ttl = tonumber(ARGV[2])
+redis.call("zremrangebyscore", KEYS[2], "-inf", now)
+redis.call("zadd", KEYS[2], now + ttl, ARGV[1])
+if redis.call("pttl", KEYS[2]) < ttl then
+	redis.call("pexpire", KEYS[2], ttl)
+end
+return 1
+`)
+
+	// KEYS: holders. ARGV: value, ttl ms, limit. Return 1 if obtained.
+	scriptObtainSemaphore = redis.NewScript(luaNow + `
+local ttl = tonumber(ARGV[2])
+redis.call("zremrangebyscore", KEYS[1], "-inf", now)
+if redis.call("zcard", KEYS[1]) >= tonumber(ARGV[3]) then
+	return 0
+end
+redis.call("zadd", KEYS[1], now + ttl, ARGV[1])
+if redis.call("pttl", KEYS[1]) < ttl then
+	redis.call("pexpire", KEYS[1], ttl)
+end
+return 1
+`)
+
+	// KEYS: holders. ARGV: value, ttl ms. Return 1 if refreshed.
+	scriptMemberRefresh = redis.NewScript(luaNow + `
+local exp = redis.call("zscore", KEYS[1], ARGV[1])
+if not exp or tonumber(exp) <= now then
+	return 0
+end
+local ttl = tonumber(ARGV[2])
+redis.call("zadd", KEYS[1], now + ttl, ARGV[1])
+if redis.call("pttl", KEYS[1]) < ttl then
+	redis.call("pexpire", KEYS[1], ttl)
+end
+return 1
+`)
+
+	// KEYS: holders. ARGV: value. Return ttl ms or -3 if lock is not held.
+	scriptMemberTTL = redis.NewScript(luaNow + `
+local exp = redis.call("zscore", KEYS[1], ARGV[1])
+if not exp or tonumber(exp) <= now then
+	return -3
+end
+return tonumber(exp) - now
+`)
+
+	// KEYS: holders. ARGV: value. Return 1 if released.
+	scriptMemberRelease = redis.NewScript(luaNow + `
+local exp = redis.call("zscore", KEYS[1], ARGV[1])
+redis.call("zrem", KEYS[1], ARGV[1])
+if not exp or tonumber(exp) <= now then
+	return 0
+end
+return 1
+`)
+
+	memberScripts = &handleScripts{
+		refresh: scriptMemberRefresh,
+		ttl:     scriptMemberTTL,
+		release: scriptMemberRelease,
+	}
+)
+
+// subKey return auxilary key of lock. It shares hash slot with lock key,
+// so scripts work in redis cluster.
+func subKey(key, suffix string) string {
+
+	if i := strings.IndexByte(key, '{'); i >= 0 {
+		if j := strings.IndexByte(key[i+1:], '}'); j > 0 {
+			return key + ":" + suffix
+		}
+	}
+
+	return "{" + key + "}:" + suffix
+}
+
+// randomValue generate unique lock owner value.
+func randomValue() (string, error) {
+
+	var b = make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate lock value: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
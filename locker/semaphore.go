@@ -0,0 +1,36 @@
+package locker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tarusov/rig/logger"
+)
+
+// ObtainSemaphore method create counting semaphore lock of key, which may be
+// held by limit holders at once. Return lock handle or error.
+func (l *RedisLocker) ObtainSemaphore(ctx context.Context, key string, limit int, ttl time.Duration) (Lock, error) {
+
+	if limit <= 0 {
+		return nil, fmt.Errorf("invalid semaphore limit: %d", limit)
+	}
+
+	if err := CheckTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	value, err := ownerValue()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = l.acquire(ctx, scriptObtainSemaphore, []string{key}, value, ttl.Milliseconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).WithField("key", key).Debug("semaphore obtained")
+
+	return l.newLock(ctx, memberScripts, key, key, value, 0), nil
+}
@@ -0,0 +1,113 @@
+package locker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type (
+	// Singleflight is distributed calls deduplicator. Concurrent callers of
+	// same key on all instances wait for first caller and receive its result.
+	Singleflight struct {
+		locker    *RedisLocker
+		lockTTL   time.Duration
+		resultTTL time.Duration
+	}
+
+	// FlightFunc is deduplicated call func.
+	FlightFunc func(ctx context.Context) ([]byte, error)
+)
+
+// Defaults.
+const (
+	defaultFlightLockTTL   = 30 * time.Second
+	defaultFlightResultTTL = 10 * time.Second
+)
+
+// NewSingleflight creates new singleflight instance.
+func NewSingleflight(l *RedisLocker, opts ...singleflightOption) (*Singleflight, error) {
+
+	var so = &singleflightOptions{
+		lockTTL:   defaultFlightLockTTL,
+		resultTTL: defaultFlightResultTTL,
+	}
+
+	for _, opt := range opts {
+		opt(so)
+	}
+
+	if err := CheckTTL(so.lockTTL); err != nil {
+		return nil, err
+	}
+
+	// Zero ttl makes result permanent in redis.
+	if so.resultTTL < time.Millisecond {
+		return nil, fmt.Errorf("invalid flight result ttl: %v", so.resultTTL)
+	}
+
+	return &Singleflight{
+		locker:    l,
+		lockTTL:   so.lockTTL,
+		resultTTL: so.resultTTL,
+	}, nil
+}
+
+// Do return cached result of key or call fn under lock and cache its result
+// in redis. Callers waiting for lock receive cached result, if fn failed
+// next waiter calls fn. Errors are not cached. Call lock is taken on separate
+// key, so it does not contend with ordinary locks of key.
+func (s *Singleflight) Do(ctx context.Context, key string, fn FlightFunc) ([]byte, error) {
+
+	var resultKey = subKey(key, "result")
+
+	res, ok, err := s.result(ctx, resultKey)
+	if err != nil || ok {
+		return res, err
+	}
+
+	lock, err := s.locker.LockWait(ctx, subKey(key, "flight"), s.lockTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	err = run(ctx, lock, s.lockTTL, func(ctx context.Context) error {
+		cached, ok, err := s.result(ctx, resultKey)
+		if err != nil || ok {
+			res = cached
+			return err
+		}
+
+		if res, err = fn(ctx); err != nil {
+			return err
+		}
+
+		return s.locker.client.Set(ctx, resultKey, res, s.resultTTL).Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Forget remove cached result of key.
+func (s *Singleflight) Forget(ctx context.Context, key string) error {
+	return s.locker.client.Del(ctx, subKey(key, "result")).Err()
+}
+
+// result return cached result if any.
+func (s *Singleflight) result(ctx context.Context, key string) ([]byte, bool, error) {
+
+	res, err := s.locker.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return res, true, nil
+}
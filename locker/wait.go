@@ -0,0 +1,94 @@
+package locker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tarusov/rig/logger"
+)
+
+// waiterTTL is time, while waiter stays in queue without retry.
+const waiterTTL = 10 * time.Second
+
+// LockWait method create new redis mutex lock, it blocks until lock is
+// obtained or ctx is done. Waiters are notified on release by redis pub/sub
+// and obtain lock in FIFO order. Lock may still be taken by Obtain out of
+// order, fairness is provided between LockWait callers only. If ctx is done,
+// returned error wraps ctx.Err() and matches ErrLockNotObtained.
+func (l *RedisLocker) LockWait(ctx context.Context, key string, ttl time.Duration) (Lock, error) {
+
+	if err := CheckTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	value, err := ownerValue()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		channel = subKey(key, "released")
+		queue   = []string{subKey(key, "queue"), subKey(key, "timeouts")}
+		keys    = []string{key, subKey(key, "fence"), queue[0], queue[1]}
+	)
+
+	// Subscribe before first try, so release notification is not missed.
+	ps := l.client.Subscribe(ctx, channel)
+	defer ps.Close()
+
+	if _, err = ps.Receive(ctx); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctxError{err: ctx.Err()}
+		}
+		return nil, fmt.Errorf("failed to subscribe to lock release: %w", err)
+	}
+
+	var released = ps.Channel()
+
+	for {
+		res, err := scriptObtainWait.Run(ctx, l.client, keys, value, ttl.Milliseconds(), waiterTTL.Milliseconds()).Int64()
+		if err != nil {
+			l.dequeue(ctx, queue, value, channel)
+			if ctx.Err() != nil {
+				return nil, ctxError{err: ctx.Err()}
+			}
+			return nil, err
+		}
+
+		if res > 0 {
+			logger.FromContext(ctx).WithField("key", key).WithField("token", res).Debug("lock obtained")
+			return l.newLock(ctx, stringScripts, key, key, value, res), nil
+		}
+
+		// Retry in time to keep place in queue.
+		var wait = time.Duration(-res) * time.Millisecond
+		if wait > waiterTTL/2 {
+			wait = waiterTTL / 2
+		}
+
+		var timer = time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			l.dequeue(ctx, queue, value, channel)
+			return nil, ctxError{err: ctx.Err()}
+		case <-released:
+		case <-timer.C:
+		}
+
+		timer.Stop()
+	}
+}
+
+// dequeue remove waiter from queue. It is called when ctx may be done.
+func (l *RedisLocker) dequeue(ctx context.Context, queue []string, value, channel string) {
+
+	dCtx, dCancel := context.WithTimeout(context.Background(), l.retryTimeout)
+	defer dCancel()
+
+	if err := scriptDequeue.Run(dCtx, l.client, queue, value, channel).Err(); err != nil {
+		logger.FromContext(ctx).WithField("key", queue[0]).WithErr(err).Warn("failed to dequeue lock waiter")
+	}
+}
@@ -1,19 +1,128 @@
 package logger
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/rs/zerolog"
+)
 
 // List of pre-defined fields.
 const (
-	FieldNamePackage = "pkg"
-	FieldNameMethod  = "fn"
+	FieldNamePackage   = "pkg"
+	FieldNameMethod    = "fn"
+	FieldNameRequestID = "request_id"
+	FieldNameVersion   = "version"
+	FieldNameCommit    = "commit"
 )
 
-// normalize key and value for logging.
+// normalizeKey return key for logging.
 // TODO: possible check for screening characters.
-func normalize(k string, v interface{}) (string, string) {
+func normalizeKey(k string) string {
 	if k == "" {
-		k = "unknown_field"
+		return "unknown_field"
+	}
+	return k
+}
+
+// appendField add field to context, keeping native value type, including
+// named scalar types. Composite values (slices, maps, structs, json.Marshaler)
+// are logged as json, other values and structs without exported fields are
+// formatted as string.
+func appendField(c zerolog.Context, k string, v interface{}) zerolog.Context {
+
+	k = normalizeKey(k)
+
+	if v == nil {
+		return c.Interface(k, nil)
+	}
+
+	// Methods of nil pointers may panic.
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return c.Interface(k, nil)
+	}
+
+	switch tv := v.(type) {
+	case string:
+		return c.Str(k, tv)
+	case []byte:
+		return c.Bytes(k, tv)
+	case bool:
+		return c.Bool(k, tv)
+	case int:
+		return c.Int(k, tv)
+	case int8:
+		return c.Int8(k, tv)
+	case int16:
+		return c.Int16(k, tv)
+	case int32:
+		return c.Int32(k, tv)
+	case int64:
+		return c.Int64(k, tv)
+	case uint:
+		return c.Uint(k, tv)
+	case uint8:
+		return c.Uint8(k, tv)
+	case uint16:
+		return c.Uint16(k, tv)
+	case uint32:
+		return c.Uint32(k, tv)
+	case uint64:
+		return c.Uint64(k, tv)
+	case float32:
+		return c.Float32(k, tv)
+	case float64:
+		return c.Float64(k, tv)
+	case time.Time:
+		return c.Time(k, tv)
+	case time.Duration:
+		return c.Dur(k, tv)
+	case error:
+		return c.AnErr(k, tv)
+	case json.Marshaler:
+		return c.Interface(k, tv)
+	case fmt.Stringer:
+		return c.Stringer(k, tv)
+	}
+
+	// Named scalar types (type Port int, etc).
+	var rv = reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.String:
+		return c.Str(k, rv.String())
+	case reflect.Bool:
+		return c.Bool(k, rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return c.Int64(k, rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return c.Uint64(k, rv.Uint())
+	case reflect.Float32:
+		return c.Float32(k, float32(rv.Float()))
+	case reflect.Float64:
+		return c.Float64(k, rv.Float())
+	case reflect.Struct, reflect.Ptr:
+		// Structs with unexported fields only are marshaled as {}.
+		if data, err := json.Marshal(v); err == nil {
+			if string(data) == "{}" && hasFields(reflect.TypeOf(v)) {
+				return c.Str(k, fmt.Sprint(v))
+			}
+			return c.RawJSON(k, data)
+		}
+		return c.Interface(k, v)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return c.Interface(k, v)
+	default:
+		return c.Str(k, fmt.Sprint(v))
 	}
+}
 
-	return k, fmt.Sprint(v)
+// hasFields check type is struct (or pointer to struct) with fields.
+func hasFields(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t.NumField() > 0
 }
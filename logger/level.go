@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// SetLoggingLevel change minimum severnity for logger and all derived loggers at runtime.
+// If level description is invalid - debug level will be set.
+func (l *Logger) SetLoggingLevel(level Level) {
+	if l.level == nil {
+		return
+	}
+
+	zl, err := zerolog.ParseLevel(string(level))
+	if err != nil {
+		zl = zerolog.DebugLevel
+	}
+	atomic.StoreInt32(l.level, int32(zl))
+}
+
+// LoggingLevel return current minimum severnity.
+func (l *Logger) LoggingLevel() Level {
+	return Level(l.currentLevel().String())
+}
+
+// currentLevel return minimum level shared with parent and derived loggers.
+func (l *Logger) currentLevel() zerolog.Level {
+	if l.level == nil {
+		return l.Logger.GetLevel()
+	}
+	return zerolog.Level(atomic.LoadInt32(l.level))
+}
+
+// current return zerolog logger with current level, so disabled events are
+// not built. Logger is copied only if level was changed after its creation.
+func (l *Logger) current() *zerolog.Logger {
+	var level = l.currentLevel()
+	if l.Logger.GetLevel() == level {
+		return l.Logger
+	}
+	var zl = l.Logger.Level(level)
+	return &zl
+}
+
+// Trace, Err, Panic, WithLevel, Log, Print, Printf and Write override embedded
+// zerolog methods, so events respect runtime level.
+
+// Trace start new trace level event.
+func (l *Logger) Trace() *zerolog.Event {
+	return l.current().Trace()
+}
+
+// Err start new error level event with err, or info level event if err is nil.
+func (l *Logger) Err(err error) *zerolog.Event {
+	return l.current().Err(err)
+}
+
+// Panic start new panic level event, it panics after message is sent.
+func (l *Logger) Panic() *zerolog.Event {
+	return l.current().Panic()
+}
+
+// WithLevel start new event with given level.
+func (l *Logger) WithLevel(level zerolog.Level) *zerolog.Event {
+	return l.current().WithLevel(level)
+}
+
+// Log start new event without level.
+func (l *Logger) Log() *zerolog.Event {
+	return l.current().Log()
+}
+
+// Print send debug level event with fmt.Print formatted message.
+func (l *Logger) Print(v ...interface{}) {
+	l.current().Print(v...)
+}
+
+// Printf send debug level event with fmt.Printf formatted message.
+func (l *Logger) Printf(format string, v ...interface{}) {
+	l.current().Printf(format, v...)
+}
+
+// Write implements io.Writer, p is sent as event without level.
+func (l *Logger) Write(p []byte) (int, error) {
+	return l.current().Write(p)
+}
+
+// derive create logger, which shares level with l.
+func (l *Logger) derive(zc zerolog.Context) *Logger {
+	var zl = zc.Logger().Level(l.currentLevel())
+	return &Logger{Logger: &zl, level: l.level}
+}
@@ -4,9 +4,11 @@ package logger
 import (
 	"io"
 	"os"
+	"sort"
 	"time"
 
 	"github.com/rs/zerolog"
+	"github.com/tarusov/rig/buildinfo"
 )
 
 // Format is output formatting option.
@@ -32,9 +34,11 @@ const (
 )
 
 type (
-	// Logger struct.
+	// Logger struct. Embedded zerolog methods, which return zerolog.Logger
+	// or zerolog.Context (Level, With, Output, etc), do not share runtime level.
 	Logger struct {
 		*zerolog.Logger
+		level *int32 // Shared with derived loggers.
 	}
 
 	// loggerOptions is auxilary constructor struct.
@@ -90,88 +94,105 @@ func New(opts ...loggerOption) *Logger {
 	zerolog.TimestampFieldName = lo.timestampName
 
 	var lw = zerolog.MultiLevelWriter(lo.outputs...)
-	var zl = zerolog.New(lw).With().Timestamp().Logger().Level(lo.level)
+	var zc = zerolog.New(lw).With().Timestamp()
 
-	return &Logger{Logger: &zl}
+	// Build information is added as default fields if set via ldflags.
+	var info = buildinfo.Get()
+	if info.Version != "" {
+		zc = zc.Str(FieldNameVersion, info.Version)
+	}
+	if info.Commit != "" {
+		zc = zc.Str(FieldNameCommit, info.Commit)
+	}
+
+	// Level is shared with derived loggers, so it can be changed at runtime.
+	var (
+		level = int32(lo.level)
+		zl    = zc.Logger().Level(lo.level)
+	)
+
+	return &Logger{Logger: &zl, level: &level}
 }
 
 // Debug implements Debug method for logger.
 func (l *Logger) Debug(msg string) {
-	l.Logger.Debug().Msg(msg)
+	l.current().Debug().Msg(msg)
 }
 
 // Info implements Info method for logger.
 func (l *Logger) Info(msg string) {
-	l.Logger.Info().Msg(msg)
+	l.current().Info().Msg(msg)
 }
 
 // Warn implements Warn method for logger.
 func (l *Logger) Warn(msg string) {
-	l.Logger.Warn().Msg(msg)
+	l.current().Warn().Msg(msg)
 }
 
 // Error implements Error method for logger.
 func (l *Logger) Error(msg string) {
-	l.Logger.Error().Msg(msg)
+	l.current().Error().Msg(msg)
 }
 
 // Fatal implements Fatal method for logger.
 func (l *Logger) Fatal(msg string) {
-	l.Logger.Fatal().Msg(msg)
+	l.current().Fatal().Msg(msg)
 }
 
 // Debugf implements Debugf method for logger.
 func (l *Logger) Debugf(format string, args ...interface{}) {
-	l.Logger.Debug().Msgf(format, args...)
+	l.current().Debug().Msgf(format, args...)
 }
 
 // Infof implements Infof method for logger.
 func (l *Logger) Infof(format string, args ...interface{}) {
-	l.Logger.Info().Msgf(format, args...)
+	l.current().Info().Msgf(format, args...)
 }
 
 // Warnf implements Warnf method for logger.
 func (l *Logger) Warnf(format string, args ...interface{}) {
-	l.Logger.Warn().Msgf(format, args...)
+	l.current().Warn().Msgf(format, args...)
 }
 
 // Errorf implements Errorf method for logger.
 func (l *Logger) Errorf(format string, args ...interface{}) {
-	l.Logger.Error().Msgf(format, args...)
+	l.current().Error().Msgf(format, args...)
 }
 
 // Fatalf implements Fatalf method for logger.
 func (l *Logger) Fatalf(format string, args ...interface{}) {
-	l.Logger.Fatal().Msgf(format, args...)
+	l.current().Fatal().Msgf(format, args...)
 }
 
 // WithField implements WithField method for logger.
 func (l *Logger) WithField(key string, value interface{}) *Logger {
-	nk, nv := normalize(key, value)
-	var outzl = l.Logger.With().Str(nk, nv).Logger()
-	return &Logger{Logger: &outzl}
+	return l.derive(appendField(l.Logger.With(), key, value))
 }
 
 // Auxilary type for method WithFields (map string-interface).
 type Fields map[string]interface{}
 
-// WithFields implements WithFields method for logger.
+// WithFields implements WithFields method for logger. Fields are added in keys order.
 func (l *Logger) WithFields(fields Fields) *Logger {
 
-	var outzl = *l.Logger
-	for k, v := range fields {
-		nk, nv := normalize(k, v)
-		outzl = outzl.With().Str(nk, nv).Logger()
+	var keys = make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var zc = l.Logger.With()
+	for _, k := range keys {
+		zc = appendField(zc, k, fields[k])
 	}
 
-	return &Logger{Logger: &outzl}
+	return l.derive(zc)
 }
 
 // WithErr implements WithErr method for logger. Do not add error if no error pushed.
 func (l *Logger) WithErr(err error) *Logger {
 	if err != nil {
-		var outzl = l.Logger.With().Err(err).Logger()
-		return &Logger{Logger: &outzl}
+		return l.derive(l.Logger.With().Err(err))
 	}
 	return l
 }
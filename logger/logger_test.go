@@ -2,12 +2,19 @@ package logger_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/buildinfo"
 	"github.com/tarusov/rig/logger"
 )
 
@@ -121,3 +128,211 @@ func TestLoggerFields(t *testing.T) {
 	log.WithErr(errors.New("some_err")).Info("msg")
 	require.Containsf(t, buf.String(), "some_err", "TestLoggerFields: message error field not logged")
 }
+
+func TestLoggerSetLevel(t *testing.T) {
+
+	var (
+		buf = bytes.NewBuffer(make([]byte, 0))
+		log = logger.New(
+			logger.WithLoggingLevel(logger.LevelInfo),
+			logger.WithLoggingOutput(buf),
+		)
+		derived = log.WithField("field_one", "1")
+	)
+
+	derived.Debug("dbg")
+	require.Emptyf(t, buf.String(), "TestLoggerSetLevel: debug message logged with info level")
+
+	log.SetLoggingLevel(logger.LevelDebug)
+	require.Equalf(t, logger.LevelDebug, derived.LoggingLevel(), "TestLoggerSetLevel: derived logger level not changed")
+
+	derived.Debug("dbg")
+	require.Containsf(t, buf.String(), "dbg", "TestLoggerSetLevel: debug message not logged after level change")
+}
+
+func TestLoggerBuildInfo(t *testing.T) {
+
+	buildinfo.Version = "v1.2.3"
+	defer func() { buildinfo.Version = "" }()
+
+	var (
+		buf = bytes.NewBuffer(make([]byte, 0))
+		log = logger.New(logger.WithLoggingOutput(buf))
+	)
+
+	log.Info("msg")
+	require.Containsf(t, buf.String(), `"version":"v1.2.3"`, "TestLoggerBuildInfo: build version not logged")
+}
+
+func TestLoggerTypedFields(t *testing.T) {
+
+	var (
+		buf    = bytes.NewBuffer(make([]byte, 0))
+		log    = logger.New(logger.WithLoggingOutput(buf))
+		nilPtr *time.Time
+	)
+
+	log.WithFields(logger.Fields{
+		"int":      42,
+		"float":    1.5,
+		"bool":     true,
+		"duration": 1500 * time.Millisecond,
+		"err":      errors.New("some_err"),
+		"slice":    []string{"a", "b"},
+		"map":      map[string]int{"a": 1},
+		"nil_ptr":  nilPtr,
+		"struct":   struct{ Name string }{Name: "exported"},
+		"private":  struct{ name string }{name: "unexported"},
+	}).Info("msg")
+
+	var entry map[string]interface{}
+	err := json.Unmarshal(buf.Bytes(), &entry)
+	require.ErrorIsf(t, err, nil, "TestLoggerTypedFields: unexpected unmarshal error: %v", err)
+
+	require.Equalf(t, float64(42), entry["int"], "TestLoggerTypedFields: int field is not number")
+	require.Equalf(t, 1.5, entry["float"], "TestLoggerTypedFields: float field is not number")
+	require.Equalf(t, true, entry["bool"], "TestLoggerTypedFields: bool field is not bool")
+	require.Equalf(t, float64(1500), entry["duration"], "TestLoggerTypedFields: duration field is not number")
+	require.Equalf(t, "some_err", entry["err"], "TestLoggerTypedFields: unexpected error field")
+	require.Equalf(t, []interface{}{"a", "b"}, entry["slice"], "TestLoggerTypedFields: slice field is not array")
+	require.Equalf(t, map[string]interface{}{"a": float64(1)}, entry["map"], "TestLoggerTypedFields: map field is not object")
+	require.Nilf(t, entry["nil_ptr"], "TestLoggerTypedFields: nil pointer field is not null")
+	require.Equalf(t, map[string]interface{}{"Name": "exported"}, entry["struct"], "TestLoggerTypedFields: struct field is not object")
+	require.Equalf(t, "{unexported}", entry["private"], "TestLoggerTypedFields: unexported struct field is empty")
+
+	// Fields are written in keys order.
+	var line = buf.String()
+	require.Truef(t, strings.Index(line, `"bool"`) < strings.Index(line, `"duration"`) &&
+		strings.Index(line, `"duration"`) < strings.Index(line, `"int"`),
+		"TestLoggerTypedFields: fields are not sorted: %s", line)
+}
+
+// Named scalar types for typed fields test.
+type (
+	port  int
+	ratio float64
+	flag  bool
+	name  string
+)
+
+func TestLoggerNamedTypeFields(t *testing.T) {
+
+	var (
+		buf = bytes.NewBuffer(make([]byte, 0))
+		log = logger.New(logger.WithLoggingOutput(buf))
+	)
+
+	log.WithFields(logger.Fields{
+		"port":  port(8080),
+		"ratio": ratio(0.5),
+		"flag":  flag(true),
+		"name":  name("value"),
+	}).Info("msg")
+
+	var entry map[string]interface{}
+	err := json.Unmarshal(buf.Bytes(), &entry)
+	require.ErrorIsf(t, err, nil, "TestLoggerNamedTypeFields: unexpected unmarshal error: %v", err)
+
+	require.Equalf(t, float64(8080), entry["port"], "TestLoggerNamedTypeFields: named int field is not number")
+	require.Equalf(t, 0.5, entry["ratio"], "TestLoggerNamedTypeFields: named float field is not number")
+	require.Equalf(t, true, entry["flag"], "TestLoggerNamedTypeFields: named bool field is not bool")
+	require.Equalf(t, "value", entry["name"], "TestLoggerNamedTypeFields: unexpected named string field")
+}
+
+// countingStringer count String calls.
+type countingStringer struct {
+	calls int
+}
+
+func (s *countingStringer) String() string {
+	s.calls++
+	return "value"
+}
+
+func TestLoggerDisabledLevel(t *testing.T) {
+
+	var (
+		buf = bytes.NewBuffer(make([]byte, 0))
+		log = logger.New(
+			logger.WithLoggingLevel(logger.LevelInfo),
+			logger.WithLoggingOutput(buf),
+		)
+		arg = &countingStringer{}
+	)
+
+	require.Equalf(t, zerolog.InfoLevel, log.GetLevel(), "TestLoggerDisabledLevel: unexpected zerolog level")
+
+	// Disabled events are not built, so arguments are not formatted.
+	log.Debugf("msg %s", arg)
+	require.Equalf(t, 0, arg.calls, "TestLoggerDisabledLevel: disabled event is built")
+	require.Emptyf(t, buf.String(), "TestLoggerDisabledLevel: disabled event is logged")
+
+	log.SetLoggingLevel(logger.LevelDebug)
+	log.Debugf("msg %s", arg)
+	require.Equalf(t, 1, arg.calls, "TestLoggerDisabledLevel: enabled event is not built")
+}
+
+func TestLoggerZerologMethodsLevel(t *testing.T) {
+
+	var (
+		buf = bytes.NewBuffer(make([]byte, 0))
+		log = logger.New(logger.WithLoggingOutput(buf))
+	)
+
+	log.SetLoggingLevel(logger.LevelError)
+
+	log.Print("msg")
+	log.WithLevel(zerolog.InfoLevel).Msg("msg")
+	log.Err(nil).Msg("msg")
+	require.Emptyf(t, buf.String(), "TestLoggerZerologMethodsLevel: disabled event is logged")
+
+	log.SetLoggingLevel(logger.LevelDebug)
+
+	log.Printf("msg %d", 1)
+	require.Containsf(t, buf.String(), `"level":"debug"`, "TestLoggerZerologMethodsLevel: enabled event is not logged")
+}
+
+// newSentryServer return test sentry server and counter of received events.
+func newSentryServer() (*httptest.Server, *int32) {
+
+	var events int32
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&events, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	return server, &events
+}
+
+func TestSentryNotifierSetDSN(t *testing.T) {
+
+	var (
+		first, firstEvents   = newSentryServer()
+		second, secondEvents = newSentryServer()
+	)
+	defer first.Close()
+	defer second.Close()
+
+	var dsn = func(server *httptest.Server) string {
+		return strings.Replace(server.URL, "http://", "http://public@", 1) + "/1"
+	}
+
+	sn, err := logger.NewSentryNotifier(dsn(first))
+	require.ErrorIsf(t, err, nil, "TestSentryNotifierSetDSN: unexpected notifier error: %v", err)
+
+	var log = logger.New(logger.WithLoggingOutput(sn))
+	log.Error("first")
+	require.ErrorIsf(t, sn.Close(), nil, "TestSentryNotifierSetDSN: unexpected close error")
+
+	err = sn.SetDSN(dsn(second))
+	require.ErrorIsf(t, err, nil, "TestSentryNotifierSetDSN: unexpected set dsn error: %v", err)
+
+	log.Error("second")
+	require.ErrorIsf(t, sn.Close(), nil, "TestSentryNotifierSetDSN: unexpected close error")
+
+	require.Equalf(t, int32(1), atomic.LoadInt32(firstEvents), "TestSentryNotifierSetDSN: unexpected first server events")
+	require.Equalf(t, int32(1), atomic.LoadInt32(secondEvents), "TestSentryNotifierSetDSN: unexpected second server events")
+
+	require.Errorf(t, sn.SetDSN(""), "TestSentryNotifierSetDSN: empty dsn accepted")
+	require.Errorf(t, sn.SetDSN("invalid"), "TestSentryNotifierSetDSN: invalid dsn accepted")
+}
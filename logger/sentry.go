@@ -5,16 +5,19 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/rs/zerolog"
+	"github.com/tarusov/rig/buildinfo"
 )
 
 type (
 	// SentryNotifier implements sentry notifier.
 	SentryNotifier struct {
 		hub *sentry.Hub
+		mu  sync.Mutex
 
 		level      zerolog.Level
 		timeout    time.Duration
@@ -34,12 +37,51 @@ func NewSentryNotifier(dsn string, opts ...sentryOption) (*SentryNotifier, error
 	var sn = &SentryNotifier{
 		level:   defaultLevel,
 		timeout: 5 * time.Second,
+		release: buildinfo.Version,
 	}
 
 	for _, opt := range opts {
 		opt(sn)
 	}
 
+	var client, err = sn.newClient(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	var scope = sentry.NewScope()
+	var hub = sentry.NewHub(client, scope)
+
+	sn.hub = hub
+
+	return sn, nil
+}
+
+// SetDSN replace sentry client with new dsn (on secret rotation, etc).
+// Pending events of previous client are flushed.
+func (sn *SentryNotifier) SetDSN(dsn string) error {
+
+	if dsn == "" {
+		return errors.New("sentry dsn is empty")
+	}
+
+	var client, err = sn.newClient(dsn)
+	if err != nil {
+		return err
+	}
+
+	sn.mu.Lock()
+	defer sn.mu.Unlock()
+
+	sn.hub.Flush(sn.timeout)
+	sn.hub.BindClient(client)
+
+	return nil
+}
+
+// newClient create sentry client with notifier options.
+func (sn *SentryNotifier) newClient(dsn string) (*sentry.Client, error) {
+
 	var client, err = sentry.NewClient(sentry.ClientOptions{
 		Dsn:              dsn,
 		AttachStacktrace: sn.stacktrace,
@@ -51,12 +93,7 @@ func NewSentryNotifier(dsn string, opts ...sentryOption) (*SentryNotifier, error
 		return nil, fmt.Errorf("failed to init sentry client: %w", err)
 	}
 
-	var scope = sentry.NewScope()
-	var hub = sentry.NewHub(client, scope)
-
-	sn.hub = hub
-
-	return sn, nil
+	return client, nil
 }
 
 // Write is implemens io.Writer Write method.
@@ -93,6 +130,11 @@ func (sn *SentryNotifier) WriteLevel(zl zerolog.Level, p []byte) (int, error) {
 	return count, nil
 }
 
+// Recover sends recovered panic value to server.
+func (sn *SentryNotifier) Recover(recovered interface{}) {
+	sn.capture(fmt.Sprint(recovered), zerolog.FatalLevel, nil)
+}
+
 // capture sends final message to server.
 func (sn *SentryNotifier) capture(msg string, level zerolog.Level, extra Fields) {
 
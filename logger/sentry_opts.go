@@ -29,7 +29,7 @@ func WithSentryEnvironment(env string) sentryOption {
 	}
 }
 
-// WithSentryEnvironment setup notify release flag.
+// WithSentryRelease setup notify release flag (build version by default).
 func WithSentryRelease(release string) sentryOption {
 	return func(sn *SentryNotifier) {
 		sn.release = release
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Gauge is a GaugeVec metric.
+type Gauge interface {
+	WithLabelValues(lvs ...string) prometheus.Gauge
+}
+
+// NewDuration create histogram vec metric and register it in registry.
+// If same metric is already registered, existing one will be returned.
+func NewDuration(r Registry, name, help string, labels ...string) (Duration, error) {
+
+	var hv = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    name,
+		Help:    help,
+		Buckets: prometheus.DefBuckets,
+	}, labels)
+
+	c, err := register(r, hv)
+	if err != nil {
+		return nil, err
+	}
+
+	d, ok := c.(*prometheus.HistogramVec)
+	if !ok {
+		return nil, fmt.Errorf("metric %q already registered with other type", name)
+	}
+
+	return d, nil
+}
+
+// NewCount create counter vec metric and register it in registry.
+// If same metric is already registered, existing one will be returned.
+func NewCount(r Registry, name, help string, labels ...string) (Count, error) {
+
+	var cv = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: name,
+		Help: help,
+	}, labels)
+
+	c, err := register(r, cv)
+	if err != nil {
+		return nil, err
+	}
+
+	cnt, ok := c.(*prometheus.CounterVec)
+	if !ok {
+		return nil, fmt.Errorf("metric %q already registered with other type", name)
+	}
+
+	return cnt, nil
+}
+
+// NewGauge create gauge vec metric and register it in registry.
+// If same metric is already registered, existing one will be returned.
+func NewGauge(r Registry, name, help string, labels ...string) (Gauge, error) {
+
+	var gv = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: name,
+		Help: help,
+	}, labels)
+
+	c, err := register(r, gv)
+	if err != nil {
+		return nil, err
+	}
+
+	g, ok := c.(*prometheus.GaugeVec)
+	if !ok {
+		return nil, fmt.Errorf("metric %q already registered with other type", name)
+	}
+
+	return g, nil
+}
+
+// register collector or return already registered one.
+func register(r Registry, c prometheus.Collector) (prometheus.Collector, error) {
+
+	if err := r.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			return are.ExistingCollector, nil
+		}
+		return nil, fmt.Errorf("failed to register metric: %w", err)
+	}
+
+	return c, nil
+}
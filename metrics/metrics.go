@@ -4,6 +4,7 @@ package metrics
 import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/tarusov/rig/buildinfo"
 )
 
 type (
@@ -31,6 +32,7 @@ func New() Registry {
 	r.MustRegister(collectors.NewBuildInfoCollector())
 	r.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
 	r.MustRegister(collectors.NewGoCollector())
+	r.MustRegister(buildinfo.NewCollector())
 
 	return r
 }
@@ -8,4 +8,15 @@ type (
 		Publish(ctx context.Context, queue string, msg []byte) error
 		Close() error
 	}
+
+	// Subscriber is optional client interface of queue subscription.
+	Subscriber interface {
+		Subscribe(ctx context.Context, queue string, handler Handler) (UnsubscribeFunc, error)
+	}
+
+	// Handler is queue message handler.
+	Handler func(ctx context.Context, msg []byte)
+
+	// UnsubscribeFunc is method for subscription cancel.
+	UnsubscribeFunc func() error
 )
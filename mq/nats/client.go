@@ -30,6 +30,7 @@ type (
 		reconnectWait   time.Duration
 		rootCAs         []string
 		token           string
+		tokenHandler    func() string
 		user            string
 	}
 )
@@ -110,6 +111,10 @@ func New(servers []string, opts ...clientOption) (mq.Client, error) {
 		})
 	}
 
+	if co.tokenHandler != nil {
+		natsOpts = append(natsOpts, nats.TokenHandler(co.tokenHandler))
+	}
+
 	c.conn, err = nats.Connect("", natsOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect NATS: %w", err)
@@ -123,6 +128,20 @@ func (c *Client) Publish(_ context.Context, queue string, msg []byte) error {
 	return c.conn.Publish(queue, msg)
 }
 
+// Subscribe method implements mq.Subscriber Subscribe method. Handler is called
+// with given context.
+func (c *Client) Subscribe(ctx context.Context, queue string, handler mq.Handler) (mq.UnsubscribeFunc, error) {
+
+	sub, err := c.conn.Subscribe(queue, func(m *nats.Msg) {
+		handler(ctx, m.Data)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe %q: %w", queue, err)
+	}
+
+	return sub.Unsubscribe, nil
+}
+
 // Close method implements mq.Client Close method.
 func (c *Client) Close() error {
 	c.conn.Close()
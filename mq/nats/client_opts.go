@@ -56,6 +56,14 @@ func WithCredentials(token, user, password string) clientOption {
 	}
 }
 
+// WithTokenHandler setup token func, it is called on each (re)connection,
+// so rotated token is used without client recreation (secrets.Secret Value, etc).
+func WithTokenHandler(fn func() string) clientOption {
+	return func(co *clientOptions) {
+		co.tokenHandler = fn
+	}
+}
+
 // WithPingInterval setup ping interval.
 func WithPingInterval(interval time.Duration) clientOption {
 	return func(co *clientOptions) {
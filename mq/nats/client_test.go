@@ -0,0 +1,74 @@
+package nats_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/mq/nats"
+)
+
+// fakeServer accept single connection, require auth and send received
+// connect token into tokens channel.
+func fakeServer(t *testing.T) (net.Listener, <-chan string) {
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.ErrorIsf(t, err, nil, "%s: unexpected listen error: %v", t.Name(), err)
+
+	var tokens = make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, err = conn.Write([]byte(`INFO {"server_id":"test","version":"2.7.3","auth_required":true,"max_payload":1048576}` + "\r\n"))
+		assert.ErrorIsf(t, err, nil, "%s: unexpected write error: %v", t.Name(), err)
+
+		var r = bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			switch {
+			case strings.HasPrefix(line, "CONNECT "):
+				var connect struct {
+					Token string `json:"auth_token"`
+				}
+				err = json.Unmarshal([]byte(strings.TrimPrefix(line, "CONNECT ")), &connect)
+				assert.ErrorIsf(t, err, nil, "%s: unexpected connect decode error: %v", t.Name(), err)
+				tokens <- connect.Token
+			case strings.HasPrefix(line, "PING"):
+				if _, err = conn.Write([]byte("PONG\r\n")); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	return ln, tokens
+}
+
+func TestWithTokenHandler(t *testing.T) {
+
+	var ln, tokens = fakeServer(t)
+	defer ln.Close()
+
+	var calls int
+	c, err := nats.New([]string{"nats://" + ln.Addr().String()}, nats.WithTokenHandler(func() string {
+		calls++
+		return "rotated-token"
+	}))
+	require.ErrorIsf(t, err, nil, "TestWithTokenHandler: unexpected connect error: %v", err)
+	defer c.Close()
+
+	require.Equalf(t, "rotated-token", <-tokens, "TestWithTokenHandler: unexpected connect token")
+	require.Equalf(t, 1, calls, "TestWithTokenHandler: unexpected token handler calls")
+}
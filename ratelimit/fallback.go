@@ -0,0 +1,35 @@
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/tarusov/rig/logger"
+)
+
+// fallback is limiter, which use secondary limiter if primary fails.
+type fallback struct {
+	primary   Limiter
+	secondary Limiter
+}
+
+// Fallback return limiter, which use secondary limiter (usually Memory)
+// while primary limiter fails, for example when redis is not available.
+func Fallback(primary, secondary Limiter) Limiter {
+	return &fallback{
+		primary:   primary,
+		secondary: secondary,
+	}
+}
+
+// AllowN report whether n events of key may happen now under limit.
+func (f *fallback) AllowN(ctx context.Context, key string, limit Limit, n int) (Result, error) {
+
+	res, err := f.primary.AllowN(ctx, key, limit, n)
+	if err == nil {
+		return res, nil
+	}
+
+	logger.FromContext(ctx).WithErr(err).Warn("rate limiter failed, fallback is used")
+
+	return f.secondary.AllowN(ctx, key, limit, n)
+}
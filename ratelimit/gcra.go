@@ -0,0 +1,120 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// GCRA is redis generic cell rate algorithm limiter. It allows burst of
+// events and then smooth rate.
+type GCRA struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// KEYS: key. ARGV: burst, rate, period ms, cost.
+// Return allowed, remaining, retry after ms, reset after ms.
+var scriptGCRA = redis.NewScript(`
+local burst = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local period = tonumber(ARGV[3])
+local cost = tonumber(ARGV[4])
+
+local t = redis.call("time")
+local now = tonumber(t[1]) * 1000 + tonumber(t[2]) / 1000
+
+local interval = period / rate
+local tat = tonumber(redis.call("get", KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+
+local new_tat = tat + interval * cost
+local diff = now - (new_tat - interval * burst)
+if diff < 0 then
+	return {0, 0, tostring(-diff), tostring(tat - now)}
+end
+
+redis.call("set", KEYS[1], tostring(new_tat), "PX", math.ceil(new_tat - now))
+return {1, math.floor(diff / interval), "0", tostring(new_tat - now)}
+`)
+
+// NewGCRA creates new redis GCRA limiter instance.
+func NewGCRA(client redis.UniversalClient, opts ...limiterOption) *GCRA {
+
+	var lo = newLimiterOptions(opts)
+
+	return &GCRA{
+		client: client,
+		prefix: lo.prefix,
+	}
+}
+
+// AllowN report whether n events of key may happen now under limit.
+func (g *GCRA) AllowN(ctx context.Context, key string, limit Limit, n int) (Result, error) {
+
+	if err := limit.validate(); err != nil {
+		return Result{}, err
+	}
+	if err := validateN(n); err != nil {
+		return Result{}, err
+	}
+
+	res, err := scriptGCRA.Run(ctx, g.client, []string{g.prefix + key},
+		limit.burst(), limit.Rate, limit.Period.Milliseconds(), n,
+	).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	return parseResult(res)
+}
+
+// parseResult convert script reply into result.
+func parseResult(res []interface{}) (Result, error) {
+
+	if len(res) != 4 {
+		return Result{}, fmt.Errorf("unexpected limiter reply: %v", res)
+	}
+
+	allowed, _ := res[0].(int64)
+	remaining, _ := res[1].(int64)
+
+	retryAfter, err := parseMillis(res[2])
+	if err != nil {
+		return Result{}, err
+	}
+
+	resetAfter, err := parseMillis(res[3])
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Allowed:    allowed == 1,
+		Remaining:  int(remaining),
+		RetryAfter: retryAfter,
+		ResetAfter: resetAfter,
+	}, nil
+}
+
+// parseMillis convert script ms reply into duration.
+func parseMillis(v interface{}) (time.Duration, error) {
+
+	switch ms := v.(type) {
+	case int64:
+		return time.Duration(ms) * time.Millisecond, nil
+	case string:
+		f, err := strconv.ParseFloat(ms, 64)
+		if err != nil {
+			return 0, fmt.Errorf("unexpected limiter reply: %w", err)
+		}
+		return time.Duration(f * float64(time.Millisecond)), nil
+	}
+
+	return 0, fmt.Errorf("unexpected limiter reply: %v", v)
+}
@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory is in-process GCRA limiter. It may be used as fallback of redis
+// limiters or in single instance services.
+type Memory struct {
+	mu        sync.Mutex
+	tats      map[string]time.Time
+	lastSweep time.Time
+}
+
+// sweepInterval is interval of expired keys removal.
+const sweepInterval = time.Minute
+
+// NewMemory creates new in-process limiter instance.
+func NewMemory() *Memory {
+	return &Memory{
+		tats:      make(map[string]time.Time),
+		lastSweep: time.Now(),
+	}
+}
+
+// AllowN report whether n events of key may happen now under limit.
+func (m *Memory) AllowN(_ context.Context, key string, limit Limit, n int) (Result, error) {
+
+	if err := limit.validate(); err != nil {
+		return Result{}, err
+	}
+	if err := validateN(n); err != nil {
+		return Result{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var now = time.Now()
+	m.sweep(now)
+
+	var (
+		interval = limit.Period / time.Duration(limit.Rate)
+		tat      = m.tats[key]
+	)
+
+	if interval <= 0 {
+		interval = 1
+	}
+
+	if tat.Before(now) {
+		tat = now
+	}
+
+	var (
+		newTAT = tat.Add(interval * time.Duration(n))
+		diff   = now.Sub(newTAT.Add(-interval * time.Duration(limit.burst())))
+	)
+
+	if diff < 0 {
+		return Result{
+			RetryAfter: -diff,
+			ResetAfter: tat.Sub(now),
+		}, nil
+	}
+
+	m.tats[key] = newTAT
+
+	return Result{
+		Allowed:    true,
+		Remaining:  int(diff / interval),
+		ResetAfter: newTAT.Sub(now),
+	}, nil
+}
+
+// sweep remove restored keys. Must be called under mutex.
+func (m *Memory) sweep(now time.Time) {
+
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+
+	for key, tat := range m.tats {
+		if !tat.After(now) {
+			delete(m.tats, key)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/tarusov/rig/httpmw"
+	"github.com/tarusov/rig/logger"
+)
+
+// KeyFunc return rate limit key of request.
+type KeyFunc func(r *http.Request) string
+
+// Rate limit headers.
+const (
+	HeaderRetryAfter = "Retry-After"
+	HeaderLimit      = "X-RateLimit-Limit"
+	HeaderRemaining  = "X-RateLimit-Remaining"
+)
+
+// Middleware limit requests rate by key, client ip by default. Requests over
+// limit get 429 response with Retry-After header. Requests are passed if
+// limiter fails.
+func Middleware(l Limiter, limit Limit, opts ...middlewareOption) httpmw.Middleware {
+
+	var mo = &middlewareOptions{
+		keyFunc: clientIP,
+	}
+
+	for _, opt := range opts {
+		opt(mo)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			res, err := Allow(r.Context(), l, mo.keyFunc(r), limit)
+			if err != nil {
+				logger.FromContext(r.Context()).WithErr(err).Warn("failed to check rate limit")
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set(HeaderLimit, strconv.Itoa(limit.Rate))
+			w.Header().Set(HeaderRemaining, strconv.Itoa(res.Remaining))
+
+			if !res.Allowed {
+				var seconds = int(math.Ceil(res.RetryAfter.Seconds()))
+				if seconds < 1 {
+					seconds = 1
+				}
+				w.Header().Set(HeaderRetryAfter, strconv.Itoa(seconds))
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP return request remote address host. Use chi RealIP middleware
+// to get client ip behind proxies.
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,106 @@
+// Package ratelimit contains redis based distributed and in-process rate
+// limiters with common Limiter interface.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type (
+	// Limiter is rate limiter interface.
+	Limiter interface {
+		// AllowN report whether n events of key may happen now under limit.
+		// Return error if n is not positive.
+		AllowN(ctx context.Context, key string, limit Limit, n int) (Result, error)
+	}
+
+	// Limit defines rate of Rate events per Period. Burst is max count of
+	// events at once, it is used by GCRA limiters only.
+	Limit struct {
+		Rate   int
+		Period time.Duration
+		Burst  int
+	}
+
+	// Result is rate limit check result.
+	Result struct {
+		// Allowed is true if events are allowed.
+		Allowed bool
+		// Remaining is count of events, which may happen now.
+		Remaining int
+		// RetryAfter is time until events are allowed, if they are not.
+		RetryAfter time.Duration
+		// ResetAfter is time until limit is fully restored.
+		ResetAfter time.Duration
+	}
+)
+
+// PerSecond return limit of rate events per second, burst equals rate.
+func PerSecond(rate int) Limit {
+	return Limit{Rate: rate, Period: time.Second, Burst: rate}
+}
+
+// PerMinute return limit of rate events per minute, burst equals rate.
+func PerMinute(rate int) Limit {
+	return Limit{Rate: rate, Period: time.Minute, Burst: rate}
+}
+
+// PerHour return limit of rate events per hour, burst equals rate.
+func PerHour(rate int) Limit {
+	return Limit{Rate: rate, Period: time.Hour, Burst: rate}
+}
+
+// validate check limit is usable.
+func (l Limit) validate() error {
+	if l.Rate <= 0 || l.Period <= 0 || l.Burst < 0 {
+		return fmt.Errorf("invalid rate limit: %d per %v, burst %d", l.Rate, l.Period, l.Burst)
+	}
+	return nil
+}
+
+// validateN check events count is positive. Non-positive count would
+// restore limit or set zero key ttl.
+func validateN(n int) error {
+	if n < 1 {
+		return fmt.Errorf("invalid events count: %d", n)
+	}
+	return nil
+}
+
+// burst return burst, it is rate if not set.
+func (l Limit) burst() int {
+	if l.Burst == 0 {
+		return l.Rate
+	}
+	return l.Burst
+}
+
+// Allow report whether single event of key may happen now.
+func Allow(ctx context.Context, l Limiter, key string, limit Limit) (Result, error) {
+	return l.AllowN(ctx, key, limit, 1)
+}
+
+// Wait blocks until single event of key is allowed or ctx is done.
+func Wait(ctx context.Context, l Limiter, key string, limit Limit) error {
+
+	for {
+		res, err := l.AllowN(ctx, key, limit, 1)
+		if err != nil {
+			return err
+		}
+		if res.Allowed {
+			return nil
+		}
+
+		var timer = time.NewTimer(res.RetryAfter)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
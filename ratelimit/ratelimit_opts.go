@@ -0,0 +1,50 @@
+package ratelimit
+
+type (
+	// limiterOption is redis limiter constructor optional modificator.
+	limiterOption func(*limiterOptions)
+
+	// limiterOptions is auxilary constructor struct.
+	limiterOptions struct {
+		prefix string
+	}
+
+	// middlewareOption is middleware optional modificator.
+	middlewareOption func(*middlewareOptions)
+
+	// middlewareOptions is auxilary middleware struct.
+	middlewareOptions struct {
+		keyFunc KeyFunc
+	}
+)
+
+// defaultPrefix is redis keys prefix.
+const defaultPrefix = "ratelimit:"
+
+// WithPrefix setup redis keys prefix ("ratelimit:" by default).
+func WithPrefix(prefix string) limiterOption {
+	return func(lo *limiterOptions) {
+		lo.prefix = prefix
+	}
+}
+
+// WithKeyFunc setup request key func (client ip by default).
+func WithKeyFunc(fn KeyFunc) middlewareOption {
+	return func(mo *middlewareOptions) {
+		mo.keyFunc = fn
+	}
+}
+
+// newLimiterOptions apply options to defaults.
+func newLimiterOptions(opts []limiterOption) *limiterOptions {
+
+	var lo = &limiterOptions{
+		prefix: defaultPrefix,
+	}
+
+	for _, opt := range opts {
+		opt(lo)
+	}
+
+	return lo
+}
@@ -0,0 +1,156 @@
+package ratelimit_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/internal/testcontainer"
+	"github.com/tarusov/rig/ratelimit"
+)
+
+// redisURI for tests. External server may be set with REDIS_URI env.
+var redisURI string
+
+func TestMain(m *testing.M) {
+
+	uri, terminate, err := testcontainer.Redis(context.Background())
+	if err != nil {
+		log.Println("redis container not inited", err)
+	}
+	redisURI = uri
+
+	var code = m.Run()
+	terminate()
+	os.Exit(code)
+}
+
+// testLimiter check limiter allows burst and then denies events.
+func testLimiter(t *testing.T, l ratelimit.Limiter) {
+
+	var (
+		ctx   = context.Background()
+		key   = uuid.NewString()
+		limit = ratelimit.Limit{Rate: 10, Period: time.Second, Burst: 3}
+	)
+
+	for i := 0; i < 3; i++ {
+		res, err := ratelimit.Allow(ctx, l, key, limit)
+		require.ErrorIsf(t, err, nil, "%s: unexpected allow error: %v", t.Name(), err)
+		require.Truef(t, res.Allowed, "%s: event %d is not allowed", t.Name(), i)
+	}
+
+	res, err := ratelimit.Allow(ctx, l, key, limit)
+	require.ErrorIsf(t, err, nil, "%s: unexpected allow error: %v", t.Name(), err)
+	require.Falsef(t, res.Allowed, "%s: event over limit is allowed", t.Name())
+	require.Truef(t, res.RetryAfter > 0 && res.RetryAfter <= 100*time.Millisecond, "%s: unexpected retry after: %v", t.Name(), res.RetryAfter)
+
+	wCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	err = ratelimit.Wait(wCtx, l, key, limit)
+	require.ErrorIsf(t, err, nil, "%s: unexpected wait error: %v", t.Name(), err)
+
+	_, err = ratelimit.Allow(ctx, l, key, ratelimit.Limit{})
+	require.Errorf(t, err, "%s: invalid limit is accepted", t.Name())
+
+	for _, n := range []int{0, -1} {
+		_, err = l.AllowN(ctx, key, limit, n)
+		require.Errorf(t, err, "%s: invalid events count %d is accepted", t.Name(), n)
+	}
+}
+
+func TestMemory(t *testing.T) {
+	testLimiter(t, ratelimit.NewMemory())
+}
+
+func TestGCRA(t *testing.T) {
+
+	if redisURI == "" {
+		t.Skip("server not inited")
+	}
+
+	opts, err := redis.ParseURL(redisURI)
+	require.ErrorIsf(t, err, nil, "TestGCRA: unexpected parse uri: %v", err)
+
+	testLimiter(t, ratelimit.NewGCRA(redis.NewClient(opts)))
+}
+
+func TestSlidingWindow(t *testing.T) {
+
+	if redisURI == "" {
+		t.Skip("server not inited")
+	}
+
+	opts, err := redis.ParseURL(redisURI)
+	require.ErrorIsf(t, err, nil, "TestSlidingWindow: unexpected parse uri: %v", err)
+
+	var (
+		l     = ratelimit.NewSlidingWindow(redis.NewClient(opts))
+		ctx   = context.Background()
+		key   = uuid.NewString()
+		limit = ratelimit.Limit{Rate: 3, Period: 300 * time.Millisecond}
+	)
+
+	res, err := l.AllowN(ctx, key, limit, 3)
+	require.ErrorIsf(t, err, nil, "TestSlidingWindow: unexpected allow error: %v", err)
+	require.Truef(t, res.Allowed, "TestSlidingWindow: events are not allowed")
+	require.Equalf(t, 0, res.Remaining, "TestSlidingWindow: unexpected remaining")
+
+	res, err = ratelimit.Allow(ctx, l, key, limit)
+	require.ErrorIsf(t, err, nil, "TestSlidingWindow: unexpected allow error: %v", err)
+	require.Falsef(t, res.Allowed, "TestSlidingWindow: event over limit is allowed")
+	require.Truef(t, res.RetryAfter > 0 && res.RetryAfter <= limit.Period, "TestSlidingWindow: unexpected retry after: %v", res.RetryAfter)
+
+	time.Sleep(res.RetryAfter + 10*time.Millisecond)
+
+	res, err = ratelimit.Allow(ctx, l, key, limit)
+	require.ErrorIsf(t, err, nil, "TestSlidingWindow: unexpected allow error: %v", err)
+	require.Truef(t, res.Allowed, "TestSlidingWindow: event is not allowed after window")
+
+	_, err = l.AllowN(ctx, key, limit, 0)
+	require.Errorf(t, err, "TestSlidingWindow: invalid events count is accepted")
+}
+
+func TestFallback(t *testing.T) {
+
+	// Primary limiter is not available.
+	var client = redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer client.Close()
+
+	testLimiter(t, ratelimit.Fallback(ratelimit.NewGCRA(client), ratelimit.NewMemory()))
+}
+
+func TestMiddleware(t *testing.T) {
+
+	var mux = chi.NewMux()
+
+	mux.Use(ratelimit.Middleware(ratelimit.NewMemory(), ratelimit.PerMinute(1)))
+	mux.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+
+	var rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equalf(t, http.StatusOK, rec.Code, "TestMiddleware: unexpected status")
+	require.Equalf(t, "1", rec.Header().Get(ratelimit.HeaderLimit), "TestMiddleware: unexpected limit header")
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equalf(t, http.StatusTooManyRequests, rec.Code, "TestMiddleware: unexpected status")
+	require.Equalf(t, "60", rec.Header().Get(ratelimit.HeaderRetryAfter), "TestMiddleware: unexpected retry after header")
+
+	// Other clients are not limited.
+	var req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equalf(t, http.StatusOK, rec.Code, "TestMiddleware: unexpected status")
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// SlidingWindow is redis sliding log limiter. It allows at most Rate events
+// during any Period, burst is not used.
+type SlidingWindow struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// KEYS: key. ARGV: rate, period ms, cost, member prefix.
+// Return allowed, remaining, retry after ms, reset after ms.
+var scriptSlidingWindow = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+
+local t = redis.call("time")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+redis.call("zremrangebyscore", KEYS[1], "-inf", now - period)
+local count = redis.call("zcard", KEYS[1])
+
+if count + cost > rate then
+	local retry = period
+	if cost <= rate then
+		local oldest = redis.call("zrange", KEYS[1], count + cost - rate - 1, count + cost - rate - 1, "withscores")
+		retry = tonumber(oldest[2]) + period - now
+	end
+	local reset = period
+	local newest = redis.call("zrange", KEYS[1], -1, -1, "withscores")
+	if newest[2] then
+		reset = tonumber(newest[2]) + period - now
+	end
+	return {0, rate - count, retry, reset}
+end
+
+for i = 1, cost do
+	redis.call("zadd", KEYS[1], now, ARGV[4] .. i)
+end
+redis.call("pexpire", KEYS[1], period)
+return {1, rate - count - cost, 0, period}
+`)
+
+// NewSlidingWindow creates new redis sliding window limiter instance.
+func NewSlidingWindow(client redis.UniversalClient, opts ...limiterOption) *SlidingWindow {
+
+	var lo = newLimiterOptions(opts)
+
+	return &SlidingWindow{
+		client: client,
+		prefix: lo.prefix,
+	}
+}
+
+// AllowN report whether n events of key may happen now under limit.
+func (sw *SlidingWindow) AllowN(ctx context.Context, key string, limit Limit, n int) (Result, error) {
+
+	if err := limit.validate(); err != nil {
+		return Result{}, err
+	}
+	if err := validateN(n); err != nil {
+		return Result{}, err
+	}
+
+	var b = make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return Result{}, fmt.Errorf("failed to generate event id: %w", err)
+	}
+
+	res, err := scriptSlidingWindow.Run(ctx, sw.client, []string{sw.prefix + key},
+		limit.Rate, limit.Period.Milliseconds(), n, base64.RawURLEncoding.EncodeToString(b)+":",
+	).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	return parseResult(res)
+}
@@ -0,0 +1,174 @@
+package redisclient
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/tarusov/rig/logger"
+	"github.com/tarusov/rig/metrics"
+)
+
+type (
+	// hook is go-redis hook, which records traces, latency metrics and
+	// logs slow commands.
+	hook struct {
+		name          string
+		tracer        opentracing.Tracer
+		slowThreshold time.Duration
+		duration      metrics.Duration
+	}
+
+	// hookState is command processing state, stored in context.
+	hookState struct {
+		start time.Time
+		span  opentracing.Span
+	}
+
+	// hookStateKey is context key of hook state.
+	hookStateKey struct{}
+)
+
+// Command results.
+const (
+	resultSuccess = "success"
+	resultError   = "error"
+)
+
+// componentName is opentracing component tag value.
+const componentName = "go-redis"
+
+// pipelineCommand is operation name of pipelines.
+const pipelineCommand = "pipeline"
+
+// newHook creates hook by client options. If tracer is not set, global
+// tracer will be used.
+func newHook(co *clientOptions) (*hook, error) {
+
+	var h = &hook{
+		name:          co.name,
+		tracer:        co.tracer,
+		slowThreshold: co.slowThreshold,
+	}
+
+	if h.tracer == nil {
+		h.tracer = opentracing.GlobalTracer()
+	}
+
+	if co.registry != nil {
+		var err error
+
+		h.duration, err = metrics.NewDuration(co.registry,
+			"redis_command_duration_seconds",
+			"Duration of redis commands in seconds.",
+			"client", "command", "result",
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return h, nil
+}
+
+// BeforeProcess implements redis.Hook.
+func (h *hook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return h.before(ctx, cmd.FullName()), nil
+}
+
+// AfterProcess implements redis.Hook.
+func (h *hook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	h.after(ctx, cmd.FullName(), cmd.Err())
+	return nil
+}
+
+// BeforeProcessPipeline implements redis.Hook.
+func (h *hook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	ctx = h.before(ctx, pipelineCommand)
+	if state, ok := ctx.Value(hookStateKey{}).(*hookState); ok {
+		state.span.SetTag("db.statement", pipelineStatement(cmds))
+	}
+	return ctx, nil
+}
+
+// AfterProcessPipeline implements redis.Hook.
+func (h *hook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+
+	var err error
+	for _, cmd := range cmds {
+		if cmdErr := cmd.Err(); cmdErr != nil && cmdErr != redis.Nil {
+			err = cmdErr
+			break
+		}
+	}
+
+	h.after(ctx, pipelineCommand, err)
+	return nil
+}
+
+// before start command span.
+func (h *hook) before(ctx context.Context, command string) context.Context {
+
+	var opts = []opentracing.StartSpanOption{ext.SpanKindRPCClient}
+	if parent := opentracing.SpanFromContext(ctx); parent != nil {
+		opts = append(opts, opentracing.ChildOf(parent.Context()))
+	}
+
+	var span = h.tracer.StartSpan("redis "+command, opts...)
+	ext.Component.Set(span, componentName)
+	ext.DBType.Set(span, "redis")
+	if command != pipelineCommand {
+		ext.DBStatement.Set(span, command)
+	}
+
+	return context.WithValue(ctx, hookStateKey{}, &hookState{
+		start: time.Now(),
+		span:  span,
+	})
+}
+
+// after finish command span, record its duration and log it if command is slow.
+func (h *hook) after(ctx context.Context, command string, err error) {
+
+	var state, ok = ctx.Value(hookStateKey{}).(*hookState)
+	if !ok {
+		return
+	}
+
+	var (
+		elapsed = time.Since(state.start)
+		result  = resultSuccess
+	)
+
+	if err != nil && err != redis.Nil {
+		result = resultError
+		ext.Error.Set(state.span, true)
+		state.span.SetTag("error.message", err.Error())
+	}
+	state.span.Finish()
+
+	if h.duration != nil {
+		h.duration.WithLabelValues(h.name, command, result).Observe(elapsed.Seconds())
+	}
+
+	if h.slowThreshold > 0 && elapsed >= h.slowThreshold {
+		logger.FromContext(ctx).WithFields(logger.Fields{
+			"client":   h.name,
+			"command":  command,
+			"duration": elapsed,
+		}).Warn("slow redis command")
+	}
+}
+
+// pipelineStatement return pipeline command names. Arguments are omitted,
+// because they may contain sensitive data.
+func pipelineStatement(cmds []redis.Cmder) string {
+	var names = make([]string, 0, len(cmds))
+	for _, cmd := range cmds {
+		names = append(names, cmd.FullName())
+	}
+	return strings.Join(names, " ")
+}
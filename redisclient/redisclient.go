@@ -0,0 +1,136 @@
+// Package redisclient contains instrumented redis client constructor.
+package redisclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type (
+	// Config is redis connection config, it can be loaded by config package.
+	Config struct {
+		Mode         Mode          `env:"REDIS_MODE" default:"single" validate:"oneof=single sentinel cluster"`
+		Addrs        []string      `env:"REDIS_ADDRS" default:"localhost:6379" validate:"min=1"`
+		MasterName   string        `env:"REDIS_MASTER_NAME"` // Sentinel master name.
+		Username     string        `env:"REDIS_USERNAME"`
+		Password     string        `env:"REDIS_PASSWORD" secret:"true"`
+		DB           int           `env:"REDIS_DB"` // Not used in cluster mode.
+		PoolSize     int           `env:"REDIS_POOL_SIZE"`
+		DialTimeout  time.Duration `env:"REDIS_DIAL_TIMEOUT" default:"5s"`
+		ReadTimeout  time.Duration `env:"REDIS_READ_TIMEOUT" default:"3s"`
+		WriteTimeout time.Duration `env:"REDIS_WRITE_TIMEOUT" default:"3s"`
+	}
+
+	// Mode is redis deployment mode.
+	Mode string
+)
+
+// Mode type enum.
+const (
+	ModeSingle   Mode = "single"
+	ModeSentinel Mode = "sentinel"
+	ModeCluster  Mode = "cluster"
+)
+
+// Defaults.
+const (
+	defaultName          = "default"
+	defaultSlowThreshold = 100 * time.Millisecond
+)
+
+// New creates redis client by config. Commands are traced, their latency
+// is recorded and slow commands are logged.
+func New(cfg Config, opts ...clientOption) (redis.UniversalClient, error) {
+
+	var co = &clientOptions{
+		name:          defaultName,
+		slowThreshold: defaultSlowThreshold,
+	}
+
+	for _, opt := range opts {
+		opt(co)
+	}
+
+	if len(cfg.Addrs) == 0 {
+		return nil, errors.New("redis addrs list is empty")
+	}
+
+	var client redis.UniversalClient
+
+	switch cfg.Mode {
+	case ModeSingle, "":
+		client = redis.NewClient(&redis.Options{
+			Addr:         cfg.Addrs[0],
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			DB:           cfg.DB,
+			PoolSize:     cfg.PoolSize,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			OnConnect:    co.onConnect,
+		})
+	case ModeSentinel:
+		if cfg.MasterName == "" {
+			return nil, errors.New("redis sentinel master name is empty")
+		}
+		client = redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:    cfg.MasterName,
+			SentinelAddrs: cfg.Addrs,
+			Username:      cfg.Username,
+			Password:      cfg.Password,
+			DB:            cfg.DB,
+			PoolSize:      cfg.PoolSize,
+			DialTimeout:   cfg.DialTimeout,
+			ReadTimeout:   cfg.ReadTimeout,
+			WriteTimeout:  cfg.WriteTimeout,
+			OnConnect:     co.onConnect,
+		})
+	case ModeCluster:
+		client = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        cfg.Addrs,
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			PoolSize:     cfg.PoolSize,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			OnConnect:    co.onConnect,
+		})
+	default:
+		return nil, fmt.Errorf("unknown redis mode: %s", cfg.Mode)
+	}
+
+	h, err := newHook(co)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	client.AddHook(h)
+
+	return client, nil
+}
+
+// HealthCheck ping redis, in cluster mode each shard is pinged.
+func HealthCheck(ctx context.Context, client redis.UniversalClient) error {
+
+	if cc, ok := client.(*redis.ClusterClient); ok {
+		return cc.ForEachShard(ctx, func(ctx context.Context, shard *redis.Client) error {
+			return ping(ctx, shard)
+		})
+	}
+
+	return ping(ctx, client)
+}
+
+// ping redis node.
+func ping(ctx context.Context, client redis.Cmdable) error {
+	if err := client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("redis health check failed: %w", err)
+	}
+	return nil
+}
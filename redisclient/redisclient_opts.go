@@ -0,0 +1,60 @@
+package redisclient
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/opentracing/opentracing-go"
+	"github.com/tarusov/rig/metrics"
+)
+
+type (
+	// clientOption is client constructor optional modificator.
+	clientOption func(*clientOptions)
+
+	// clientOptions is auxilary constructor struct.
+	clientOptions struct {
+		name          string
+		tracer        opentracing.Tracer
+		registry      metrics.Registry
+		slowThreshold time.Duration
+		onConnect     func(ctx context.Context, cn *redis.Conn) error
+	}
+)
+
+// WithName setup client name, it is used as metrics label.
+func WithName(name string) clientOption {
+	return func(co *clientOptions) {
+		co.name = name
+	}
+}
+
+// WithTracer setup commands tracer.
+func WithTracer(tracer opentracing.Tracer) clientOption {
+	return func(co *clientOptions) {
+		co.tracer = tracer
+	}
+}
+
+// WithMetrics setup registry for commands latency metrics.
+func WithMetrics(registry metrics.Registry) clientOption {
+	return func(co *clientOptions) {
+		co.registry = registry
+	}
+}
+
+// WithSlowThreshold setup duration of commands to be logged as slow
+// (100ms by default), 0 disables slow commands logging.
+func WithSlowThreshold(threshold time.Duration) clientOption {
+	return func(co *clientOptions) {
+		co.slowThreshold = threshold
+	}
+}
+
+// WithOnConnect setup new connections hook, e.g. secrets.RedisOnConnect.
+func WithOnConnect(fn func(ctx context.Context, cn *redis.Conn) error) clientOption {
+	return func(co *clientOptions) {
+		co.onConnect = fn
+	}
+}
@@ -0,0 +1,138 @@
+package redisclient_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/config"
+	"github.com/tarusov/rig/internal/testcontainer"
+	"github.com/tarusov/rig/logger"
+	"github.com/tarusov/rig/metrics"
+	"github.com/tarusov/rig/redisclient"
+)
+
+// redisURI for tests. External server may be set with REDIS_URI env.
+var redisURI string
+
+func TestMain(m *testing.M) {
+
+	uri, terminate, err := testcontainer.Redis(context.Background())
+	if err != nil {
+		log.Println("redis container not inited", err)
+	}
+	redisURI = uri
+
+	var code = m.Run()
+	terminate()
+	os.Exit(code)
+}
+
+// testConfig return config of test redis, skip test if redis is not available.
+func testConfig(t *testing.T) redisclient.Config {
+
+	if redisURI == "" {
+		t.Skip("redis is not available")
+	}
+
+	opts, err := redis.ParseURL(redisURI)
+	require.ErrorIsf(t, err, nil, "%s: unexpected parse url error: %v", t.Name(), err)
+
+	return redisclient.Config{
+		Mode:  redisclient.ModeSingle,
+		Addrs: []string{opts.Addr},
+	}
+}
+
+func TestConfig(t *testing.T) {
+
+	t.Setenv("TEST_REDIS_MODE", "cluster")
+	t.Setenv("TEST_REDIS_ADDRS", "node1:6379,node2:6379")
+
+	var cfg redisclient.Config
+	err := config.Load(&cfg, config.WithEnvPrefix("TEST_"))
+	require.ErrorIsf(t, err, nil, "TestConfig: unexpected load error: %v", err)
+	require.Equalf(t, redisclient.ModeCluster, cfg.Mode, "TestConfig: unexpected mode")
+	require.Equalf(t, []string{"node1:6379", "node2:6379"}, cfg.Addrs, "TestConfig: unexpected addrs")
+	require.Equalf(t, 3*time.Second, cfg.ReadTimeout, "TestConfig: unexpected read timeout")
+
+	client, err := redisclient.New(cfg)
+	require.ErrorIsf(t, err, nil, "TestConfig: unexpected new error: %v", err)
+	require.IsTypef(t, &redis.ClusterClient{}, client, "TestConfig: unexpected client type")
+	client.Close()
+
+	_, err = redisclient.New(redisclient.Config{Mode: redisclient.ModeSentinel, Addrs: cfg.Addrs})
+	require.Errorf(t, err, "TestConfig: sentinel without master name created")
+
+	_, err = redisclient.New(redisclient.Config{Mode: "unknown", Addrs: cfg.Addrs})
+	require.Errorf(t, err, "TestConfig: client with unknown mode created")
+}
+
+func TestHooks(t *testing.T) {
+
+	var (
+		buf      = bytes.NewBuffer(make([]byte, 0))
+		ctx      = logger.ContextWithLogger(context.Background(), logger.New(logger.WithLoggingOutput(buf)))
+		tracer   = mocktracer.New()
+		registry = metrics.New()
+		key      = uuid.NewString()
+	)
+
+	client, err := redisclient.New(testConfig(t),
+		redisclient.WithName("test"),
+		redisclient.WithTracer(tracer),
+		redisclient.WithMetrics(registry),
+		redisclient.WithSlowThreshold(time.Nanosecond),
+	)
+	require.ErrorIsf(t, err, nil, "TestHooks: unexpected new error: %v", err)
+	defer client.Close()
+
+	err = redisclient.HealthCheck(ctx, client)
+	require.ErrorIsf(t, err, nil, "TestHooks: unexpected health check error: %v", err)
+
+	err = client.Set(ctx, key, "value", time.Minute).Err()
+	require.ErrorIsf(t, err, nil, "TestHooks: unexpected set error: %v", err)
+
+	_, err = client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		p.Get(ctx, key)
+		p.Del(ctx, key)
+		return nil
+	})
+	require.ErrorIsf(t, err, nil, "TestHooks: unexpected pipeline error: %v", err)
+
+	var operations = make(map[string]bool)
+	for _, span := range tracer.FinishedSpans() {
+		operations[span.OperationName] = true
+	}
+	require.Truef(t, operations["redis set"], "TestHooks: command span not recorded")
+	require.Truef(t, operations["redis pipeline"], "TestHooks: pipeline span not recorded")
+
+	families, err := registry.Gather()
+	require.ErrorIsf(t, err, nil, "TestHooks: unexpected gather error: %v", err)
+
+	var found bool
+	for _, mf := range families {
+		if mf.GetName() == "redis_command_duration_seconds" {
+			found = true
+		}
+	}
+	require.Truef(t, found, "TestHooks: latency not recorded")
+
+	require.Containsf(t, buf.String(), "slow redis command", "TestHooks: slow command not logged")
+
+	// Duration is logged as number, not as string.
+	var line = buf.String()[:strings.IndexByte(buf.String(), '\n')]
+	var entry map[string]interface{}
+	err = json.Unmarshal([]byte(line), &entry)
+	require.ErrorIsf(t, err, nil, "TestHooks: unexpected log decode error: %v", err)
+	require.IsTypef(t, float64(0), entry["duration"], "TestHooks: unexpected duration type")
+}
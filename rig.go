@@ -0,0 +1,159 @@
+// Package rig contains standard microservice components bootstrap.
+package rig
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/oklog/run"
+	"github.com/opentracing/opentracing-go"
+	"github.com/tarusov/rig/config"
+	"github.com/tarusov/rig/exec"
+	"github.com/tarusov/rig/logger"
+	"github.com/tarusov/rig/metrics"
+	"github.com/tarusov/rig/tracer"
+)
+
+type (
+	// App is bootstrapped components container.
+	App struct {
+		Context context.Context // Context with logger.
+		Config  Config
+		Logger  *logger.Logger
+		Sentry  *logger.SentryNotifier // Nil if sentry dsn is not set.
+		Tracer  *tracer.Tracer         // Set as opentracing global tracer.
+		Metrics metrics.Registry
+		Group   *run.Group // Contains signal watcher and admin endpoints.
+	}
+
+	// Config is well-known env variables.
+	Config struct {
+		ServiceName        string        `env:"SERVICE_NAME" default:"unknown-service"`
+		LogLevel           logger.Level  `env:"LOG_LEVEL" default:"info"`
+		LogFormat          logger.Format `env:"LOG_FORMAT" default:"json"`
+		SentryDSN          string        `env:"SENTRY_DSN" secret:"true"`
+		SentryEnvironment  string        `env:"SENTRY_ENVIRONMENT"`
+		SentryLevel        logger.Level  `env:"SENTRY_LEVEL" default:"error"`
+		JaegerAgent        string        `env:"JAEGER_AGENT"`
+		JaegerSamplerType  string        `env:"JAEGER_SAMPLER_TYPE" default:"remote"`
+		JaegerSamplerParam float64       `env:"JAEGER_SAMPLER_PARAM"`
+		MetricsPort        int           `env:"METRICS_PORT" default:"9090"` // Admin endpoints port, 0 to disable.
+	}
+)
+
+// Bootstrap read well-known env variables and create standard components:
+// logger (put into context), sentry notifier, tracer (set globally),
+// metrics registry and run group with signal watcher and admin endpoints
+// (/health, /metrics, /version on METRICS_PORT). Components can be overridden
+// with options.
+func Bootstrap(ctx context.Context, opts ...bootstrapOption) (*App, error) {
+
+	var bo = &bootstrapOptions{}
+	for _, opt := range opts {
+		opt(bo)
+	}
+
+	var app = &App{
+		Logger:  bo.logger,
+		Sentry:  bo.sentry,
+		Tracer:  bo.tracer,
+		Metrics: bo.registry,
+		Group:   &run.Group{},
+	}
+
+	if err := config.Load(&app.Config, config.WithEnvPrefix(bo.envPrefix)); err != nil {
+		return nil, fmt.Errorf("failed to load bootstrap config: %w", err)
+	}
+
+	if app.Sentry == nil && app.Config.SentryDSN != "" {
+		sn, err := logger.NewSentryNotifier(app.Config.SentryDSN,
+			logger.WithSentryEnvironment(app.Config.SentryEnvironment),
+			logger.WithSentryNotifyLevel(app.Config.SentryLevel),
+		)
+		if err != nil {
+			return nil, err
+		}
+		app.Sentry = sn
+	}
+
+	if app.Logger == nil {
+		var outputs = []io.Writer{os.Stderr}
+		if app.Sentry != nil {
+			outputs = append(outputs, app.Sentry)
+		}
+		app.Logger = logger.New(
+			logger.WithLoggingLevel(app.Config.LogLevel),
+			logger.WithLoggingFormat(app.Config.LogFormat),
+			logger.WithLoggingOutput(outputs...),
+		).WithField("service", app.Config.ServiceName)
+	}
+
+	if app.Tracer == nil {
+		t, err := tracer.New(
+			tracer.WithServiceName(app.Config.ServiceName),
+			tracer.WithAgentAddress(app.Config.JaegerAgent),
+			tracer.WithSamplerType(app.Config.JaegerSamplerType),
+			tracer.WithSamplerParam(app.Config.JaegerSamplerParam),
+		)
+		if err != nil {
+			if app.Sentry != nil && bo.sentry == nil {
+				_ = app.Sentry.Close() // Created here, so release it.
+			}
+			return nil, fmt.Errorf("failed to create tracer: %w", err)
+		}
+		app.Tracer = t
+	}
+	opentracing.SetGlobalTracer(app.Tracer)
+
+	if app.Metrics == nil {
+		app.Metrics = metrics.New()
+	}
+
+	app.Context = logger.ContextWithLogger(ctx, app.Logger)
+
+	exec.AddSignalWatcher(app.Context, app.Group)
+	if app.Config.MetricsPort != 0 {
+		exec.AddAdminEndpoints(app.Context, app.Group, app.Metrics, app.Config.MetricsPort)
+	}
+
+	app.Logger.WithField("config", config.String(app.Config)).Debug("components bootstrapped")
+
+	return app, nil
+}
+
+// Run run group and close components after group termination.
+func (a *App) Run() error {
+
+	var err = a.Group.Run()
+	if cerr := a.Close(); cerr != nil {
+		a.Logger.WithErr(cerr).Error("failed to close components")
+	}
+
+	return err
+}
+
+// Close flush sentry events and close tracer.
+func (a *App) Close() error {
+
+	var serr, terr error
+	if a.Sentry != nil {
+		serr = a.Sentry.Close()
+	}
+
+	if a.Tracer != nil {
+		terr = a.Tracer.Close()
+	}
+
+	switch {
+	case serr != nil && terr != nil:
+		return fmt.Errorf("failed to close sentry notifier: %v; failed to close tracer: %w", serr, terr)
+	case serr != nil:
+		return fmt.Errorf("failed to close sentry notifier: %w", serr)
+	case terr != nil:
+		return fmt.Errorf("failed to close tracer: %w", terr)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package rig
+
+import (
+	"github.com/tarusov/rig/logger"
+	"github.com/tarusov/rig/metrics"
+	"github.com/tarusov/rig/tracer"
+)
+
+type (
+	// bootstrapOption is bootstrap optional modificator.
+	bootstrapOption func(*bootstrapOptions)
+
+	// bootstrapOptions is auxilary bootstrap struct.
+	bootstrapOptions struct {
+		envPrefix string
+		logger    *logger.Logger
+		sentry    *logger.SentryNotifier
+		tracer    *tracer.Tracer
+		registry  metrics.Registry
+	}
+)
+
+// WithEnvPrefix setup env variables prefix (APP_ for APP_LOG_LEVEL, etc).
+func WithEnvPrefix(prefix string) bootstrapOption {
+	return func(bo *bootstrapOptions) {
+		bo.envPrefix = prefix
+	}
+}
+
+// WithLogger override logger.
+func WithLogger(l *logger.Logger) bootstrapOption {
+	return func(bo *bootstrapOptions) {
+		bo.logger = l
+	}
+}
+
+// WithSentryNotifier override sentry notifier.
+func WithSentryNotifier(sn *logger.SentryNotifier) bootstrapOption {
+	return func(bo *bootstrapOptions) {
+		bo.sentry = sn
+	}
+}
+
+// WithTracer override tracer.
+func WithTracer(t *tracer.Tracer) bootstrapOption {
+	return func(bo *bootstrapOptions) {
+		bo.tracer = t
+	}
+}
+
+// WithRegistry override metrics registry.
+func WithRegistry(r metrics.Registry) bootstrapOption {
+	return func(bo *bootstrapOptions) {
+		bo.registry = r
+	}
+}
@@ -0,0 +1,70 @@
+package rig_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig"
+	"github.com/tarusov/rig/logger"
+)
+
+func TestBootstrap(t *testing.T) {
+
+	t.Setenv("TEST_SERVICE_NAME", "test-service")
+	t.Setenv("TEST_LOG_LEVEL", "warn")
+	t.Setenv("TEST_JAEGER_SAMPLER_TYPE", "const")
+	t.Setenv("TEST_METRICS_PORT", "35003")
+
+	app, err := rig.Bootstrap(context.Background(), rig.WithEnvPrefix("TEST_"))
+	require.ErrorIsf(t, err, nil, "TestBootstrap: unexpected bootstrap error: %v", err)
+
+	require.Equalf(t, "test-service", app.Config.ServiceName, "TestBootstrap: env config not loaded")
+	require.Equalf(t, logger.LevelWarning, app.Logger.LoggingLevel(), "TestBootstrap: logger level not set")
+	require.Equalf(t, app.Logger, logger.FromContext(app.Context), "TestBootstrap: logger not in context")
+	require.Equalf(t, app.Tracer, opentracing.GlobalTracer(), "TestBootstrap: tracer not set globally")
+	require.Nilf(t, app.Sentry, "TestBootstrap: sentry created without dsn")
+
+	var (
+		errStop = errors.New("stop")
+		stop    = make(chan struct{})
+		done    = make(chan error, 1)
+	)
+	app.Group.Add(func() error {
+		<-stop
+		return errStop
+	}, func(error) {})
+
+	go func() {
+		done <- app.Run()
+	}()
+
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		if resp, err = http.Get("http://localhost:35003/health"); err == nil {
+			resp.Body.Close()
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.ErrorIsf(t, err, nil, "TestBootstrap: admin endpoint unexpected error: %v", err)
+	require.Equalf(t, http.StatusOK, resp.StatusCode, "TestBootstrap: unexpected health status")
+
+	close(stop)
+	require.ErrorIsf(t, <-done, errStop, "TestBootstrap: unexpected run error")
+}
+
+func TestBootstrapTracerError(t *testing.T) {
+
+	t.Setenv("TEST_SENTRY_DSN", "https://public@localhost/1")
+	t.Setenv("TEST_JAEGER_SAMPLER_TYPE", "unknown")
+	t.Setenv("TEST_METRICS_PORT", "0")
+
+	app, err := rig.Bootstrap(context.Background(), rig.WithEnvPrefix("TEST_"))
+	require.Errorf(t, err, "TestBootstrapTracerError: expected tracer error")
+	require.Nilf(t, app, "TestBootstrapTracerError: unexpected app")
+}
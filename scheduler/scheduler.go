@@ -0,0 +1,292 @@
+// Package scheduler contains periodic jobs scheduler.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/tarusov/rig/locker"
+	"github.com/tarusov/rig/logger"
+	"github.com/tarusov/rig/metrics"
+)
+
+type (
+	// Scheduler struct.
+	Scheduler struct {
+		mu       sync.Mutex
+		jobs     map[string]*job
+		started  bool
+		duration metrics.Duration
+		runs     metrics.Count
+	}
+
+	// JobFunc is scheduled job func.
+	JobFunc func(ctx context.Context) error
+
+	// Schedule describes job activation times.
+	Schedule interface {
+		// Next return next activation time, later than given.
+		Next(time.Time) time.Time
+	}
+
+	// job is scheduled job.
+	job struct {
+		name     string
+		schedule Schedule
+		fn       JobFunc
+		running  int32
+
+		jitter     time.Duration
+		timeout    time.Duration
+		overlap    bool
+		locker     locker.Locker
+		lockTTL    time.Duration
+		lockPrefix string
+	}
+
+	// intervalSchedule is constant interval schedule.
+	intervalSchedule time.Duration
+)
+
+// Job run results.
+const (
+	resultSuccess = "success"
+	resultError   = "error"
+	resultSkipped = "skipped"
+)
+
+// Aux error types.
+var (
+	ErrJobExists      = errors.New("job already exists")        // Job with same name already added.
+	ErrAlreadyStarted = errors.New("scheduler already started") // Jobs can't be added after start.
+)
+
+// New creates new scheduler instance.
+func New(opts ...schedulerOption) (*Scheduler, error) {
+
+	var so = &schedulerOptions{}
+	for _, opt := range opts {
+		opt(so)
+	}
+
+	var s = &Scheduler{
+		jobs: make(map[string]*job),
+	}
+
+	if so.registry != nil {
+		var err error
+
+		s.duration, err = metrics.NewDuration(so.registry,
+			"scheduler_job_duration_seconds",
+			"Duration of scheduled job runs in seconds.",
+			"job",
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		s.runs, err = metrics.NewCount(so.registry,
+			"scheduler_job_runs_total",
+			"Total number of scheduled job runs.",
+			"job", "result",
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Next implements Schedule Next method.
+func (is intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(is))
+}
+
+// Every add job which runs with constant interval.
+func (s *Scheduler) Every(name string, interval time.Duration, fn JobFunc, opts ...jobOption) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid job %q interval: %v", name, interval)
+	}
+	return s.Add(name, intervalSchedule(interval), fn, opts...)
+}
+
+// Cron add job which runs by cron expression (standard 5 fields spec or descriptors like @hourly).
+func (s *Scheduler) Cron(name string, expr string, fn JobFunc, opts ...jobOption) error {
+	schedule, err := cron.ParseStandard(expr)
+	if err != nil {
+		return fmt.Errorf("invalid job %q cron expression: %w", name, err)
+	}
+	return s.Add(name, schedule, fn, opts...)
+}
+
+// Add job with custom schedule.
+func (s *Scheduler) Add(name string, schedule Schedule, fn JobFunc, opts ...jobOption) error {
+
+	var j = &job{
+		name:       name,
+		schedule:   schedule,
+		fn:         fn,
+		lockPrefix: defaultLockPrefix,
+	}
+
+	for _, opt := range opts {
+		opt(j)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return ErrAlreadyStarted
+	}
+	if _, ok := s.jobs[name]; ok {
+		return ErrJobExists
+	}
+	s.jobs[name] = j
+
+	return nil
+}
+
+// Run starts all jobs and blocks until context is done. Running jobs are
+// cancelled with context and waited before return.
+func (s *Scheduler) Run(ctx context.Context) error {
+
+	s.mu.Lock()
+	if s.started {
+		s.mu.Unlock()
+		return ErrAlreadyStarted
+	}
+	s.started = true
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, j := range s.jobs {
+		wg.Add(1)
+		go func(j *job) {
+			defer wg.Done()
+			s.loop(ctx, j, &wg)
+		}(j)
+	}
+
+	logger.FromContext(ctx).WithField("jobs", len(s.jobs)).Info("scheduler started")
+	<-ctx.Done()
+
+	wg.Wait()
+	logger.FromContext(ctx).Info("scheduler stopped")
+
+	return nil
+}
+
+// loop wait for job activation times and run job until context is done.
+func (s *Scheduler) loop(ctx context.Context, j *job, wg *sync.WaitGroup) {
+
+	var log = logger.FromContext(ctx).WithField("job", j.name)
+
+	for {
+		var next = j.schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Warn("job has no next activation time")
+			return
+		}
+		if j.jitter > 0 {
+			next = next.Add(time.Duration(rand.Int63n(int64(j.jitter))))
+		}
+
+		var timer = time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if !j.overlap && !atomic.CompareAndSwapInt32(&j.running, 0, 1) {
+			log.Warn("job is still running, run skipped")
+			s.observe(j, resultSkipped, 0)
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !j.overlap {
+				defer atomic.StoreInt32(&j.running, 0)
+			}
+			s.execute(logger.ContextWithLogger(ctx, log), j)
+		}()
+	}
+}
+
+// execute run job once.
+func (s *Scheduler) execute(ctx context.Context, j *job) {
+
+	var log = logger.FromContext(ctx)
+
+	if j.locker != nil {
+		// Locker is single try, so other instances skip activation at once.
+		// Lock is refreshed while job runs and is not released after run, so
+		// other instances skip same activation until ttl is expired.
+		lock, err := j.locker.Obtain(ctx, j.lockPrefix+j.name, j.lockTTL)
+		if err != nil {
+			if !errors.Is(err, locker.ErrLockNotObtained) {
+				log.WithErr(err).Error("failed to obtain job lock")
+			}
+			s.observe(j, resultSkipped, 0)
+			return
+		}
+
+		var stop context.CancelFunc
+		ctx, stop = locker.AutoRefresh(ctx, lock, j.lockTTL)
+		defer stop()
+	}
+
+	if j.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.timeout)
+		defer cancel()
+	}
+
+	var start = time.Now()
+	log.Debug("job started")
+
+	var err = safeCall(ctx, j.fn)
+	var elapsed = time.Since(start)
+
+	if err != nil {
+		log.WithErr(err).WithField("duration", elapsed).Error("job failed")
+		s.observe(j, resultError, elapsed)
+		return
+	}
+
+	log.WithField("duration", elapsed).Info("job completed")
+	s.observe(j, resultSuccess, elapsed)
+}
+
+// observe write job run metrics.
+func (s *Scheduler) observe(j *job, result string, elapsed time.Duration) {
+	if s.runs != nil {
+		s.runs.WithLabelValues(j.name, result).Inc()
+	}
+	if s.duration != nil && result != resultSkipped {
+		s.duration.WithLabelValues(j.name).Observe(elapsed.Seconds())
+	}
+}
+
+// safeCall call job func and convert panic into error.
+func safeCall(ctx context.Context, fn JobFunc) (err error) {
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			logger.FromContext(ctx).WithField("stack", string(debug.Stack())).Error("job panic recovered")
+			err = fmt.Errorf("job panic: %v", rvr)
+		}
+	}()
+	return fn(ctx)
+}
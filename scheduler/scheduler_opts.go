@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/tarusov/rig/locker"
+	"github.com/tarusov/rig/metrics"
+)
+
+type (
+	// schedulerOption is scheduler constructor optional modificator.
+	schedulerOption func(*schedulerOptions)
+
+	// schedulerOptions is auxilary constructor struct.
+	schedulerOptions struct {
+		registry metrics.Registry
+	}
+
+	// jobOption is job optional modificator.
+	jobOption func(*job)
+)
+
+// defaultLockPrefix is cluster lock key prefix.
+const defaultLockPrefix = "scheduler:"
+
+// WithMetrics setup registry for job runs metrics.
+func WithMetrics(registry metrics.Registry) schedulerOption {
+	return func(so *schedulerOptions) {
+		so.registry = registry
+	}
+}
+
+// WithJitter add random delay [0, jitter) to each job activation.
+func WithJitter(jitter time.Duration) jobOption {
+	return func(j *job) {
+		j.jitter = jitter
+	}
+}
+
+// WithTimeout limit job run duration.
+func WithTimeout(timeout time.Duration) jobOption {
+	return func(j *job) {
+		j.timeout = timeout
+	}
+}
+
+// WithOverlap allow job run while previous run is not completed.
+// By default such activations are skipped.
+func WithOverlap() jobOption {
+	return func(j *job) {
+		j.overlap = true
+	}
+}
+
+// WithClusterLock setup cluster-wide single execution. Locker must be single
+// try (created with WithRetryCount(0)), otherwise other instances wait for
+// lock and run same activation late. Lock is refreshed while job runs and is
+// not released after run, so ttl should be less than job interval.
+func WithClusterLock(l locker.Locker, ttl time.Duration) jobOption {
+	return func(j *job) {
+		j.locker = l
+		j.lockTTL = ttl
+	}
+}
+
+// WithLockPrefix setup cluster lock key prefix ("scheduler:" by default).
+func WithLockPrefix(prefix string) jobOption {
+	return func(j *job) {
+		j.lockPrefix = prefix
+	}
+}
@@ -0,0 +1,116 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/locker/memory"
+	"github.com/tarusov/rig/metrics"
+	"github.com/tarusov/rig/scheduler"
+)
+
+func TestSchedulerEvery(t *testing.T) {
+
+	s, err := scheduler.New(scheduler.WithMetrics(metrics.New()))
+	require.ErrorIsf(t, err, nil, "TestSchedulerEvery: unexpected scheduler error: %v", err)
+
+	var runs int32
+	err = s.Every("counter", 10*time.Millisecond, func(context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	})
+	require.ErrorIsf(t, err, nil, "TestSchedulerEvery: unexpected add error: %v", err)
+
+	err = s.Every("counter", time.Second, func(context.Context) error { return nil })
+	require.ErrorIsf(t, err, scheduler.ErrJobExists, "TestSchedulerEvery: unexpected add error: %v", err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err = s.Run(ctx)
+	require.ErrorIsf(t, err, nil, "TestSchedulerEvery: unexpected run error: %v", err)
+	require.GreaterOrEqualf(t, atomic.LoadInt32(&runs), int32(3), "TestSchedulerEvery: job runs too rare")
+}
+
+func TestSchedulerOverlap(t *testing.T) {
+
+	s, err := scheduler.New()
+	require.ErrorIsf(t, err, nil, "TestSchedulerOverlap: unexpected scheduler error: %v", err)
+
+	var runs, cancelled int32
+	err = s.Every("slow", 10*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		<-ctx.Done()
+		atomic.AddInt32(&cancelled, 1)
+		return ctx.Err()
+	})
+	require.ErrorIsf(t, err, nil, "TestSchedulerOverlap: unexpected add error: %v", err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err = s.Run(ctx)
+	require.ErrorIsf(t, err, nil, "TestSchedulerOverlap: unexpected run error: %v", err)
+	require.Equalf(t, int32(1), atomic.LoadInt32(&runs), "TestSchedulerOverlap: overlapped job runs")
+	require.Equalf(t, int32(1), atomic.LoadInt32(&cancelled), "TestSchedulerOverlap: running job not waited")
+}
+
+func TestSchedulerCron(t *testing.T) {
+
+	s, err := scheduler.New()
+	require.ErrorIsf(t, err, nil, "TestSchedulerCron: unexpected scheduler error: %v", err)
+
+	err = s.Cron("invalid", "* * *", func(context.Context) error { return nil })
+	require.Errorf(t, err, "TestSchedulerCron: invalid expression accepted")
+
+	err = s.Cron("hourly", "@hourly", func(context.Context) error { return errors.New("not expected") })
+	require.ErrorIsf(t, err, nil, "TestSchedulerCron: unexpected add error: %v", err)
+}
+
+func TestSchedulerClusterLock(t *testing.T) {
+
+	var (
+		l    = memory.New(memory.WithRetryCount(0))
+		runs int32
+	)
+
+	var newScheduler = func() *scheduler.Scheduler {
+		s, err := scheduler.New()
+		require.ErrorIsf(t, err, nil, "TestSchedulerClusterLock: unexpected scheduler error: %v", err)
+
+		err = s.Every("job", 20*time.Millisecond, func(ctx context.Context) error {
+			atomic.AddInt32(&runs, 1)
+			<-ctx.Done()
+			return nil
+		}, scheduler.WithClusterLock(l, 60*time.Millisecond))
+		require.ErrorIsf(t, err, nil, "TestSchedulerClusterLock: unexpected add error: %v", err)
+
+		return s
+	}
+
+	var (
+		start   = time.Now()
+		first   = newScheduler()
+		second  = newScheduler()
+		done    = make(chan error, 2)
+		ctx, cf = context.WithTimeout(context.Background(), 300*time.Millisecond)
+	)
+	defer cf()
+
+	go func() { done <- first.Run(ctx) }()
+	go func() { done <- second.Run(ctx) }()
+
+	for i := 0; i < 2; i++ {
+		err := <-done
+		require.ErrorIsf(t, err, nil, "TestSchedulerClusterLock: unexpected run error: %v", err)
+	}
+
+	// Job lock is refreshed while job runs longer than lock ttl, and losing
+	// instance does not wait for lock with single try locker.
+	require.Equalf(t, int32(1), atomic.LoadInt32(&runs), "TestSchedulerClusterLock: job runs on both instances")
+	require.Lessf(t, time.Since(start), time.Second, "TestSchedulerClusterLock: losing instance waited for lock")
+}
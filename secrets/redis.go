@@ -0,0 +1,19 @@
+package secrets
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisOnConnect create redis.Options OnConnect hook which authenticate new
+// connections with current password value, so rotated password is used for
+// new connections without client recreation. Username is optional (ACL).
+func RedisOnConnect(username string, password *Secret) func(ctx context.Context, cn *redis.Conn) error {
+	return func(ctx context.Context, cn *redis.Conn) error {
+		if username != "" {
+			return cn.AuthACL(ctx, username, password.Value()).Err()
+		}
+		return cn.Auth(ctx, password.Value()).Err()
+	}
+}
@@ -0,0 +1,124 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tarusov/rig/logger"
+)
+
+type (
+	// Secret is refreshable secret value.
+	Secret struct {
+		provider Provider
+		name     string
+		interval time.Duration
+
+		mu    sync.RWMutex
+		value string
+		subs  []RotateFunc
+	}
+
+	// RotateFunc is secret rotation callback.
+	RotateFunc func(value string)
+)
+
+// Defaults.
+const (
+	defaultRefreshInterval = time.Minute
+)
+
+// NewSecret create new secret and fetch its value from provider.
+func NewSecret(ctx context.Context, provider Provider, name string, opts ...secretOption) (*Secret, error) {
+
+	var s = &Secret{
+		provider: provider,
+		name:     name,
+		interval: defaultRefreshInterval,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.interval <= 0 {
+		return nil, fmt.Errorf("invalid secret refresh interval: %v", s.interval)
+	}
+
+	value, err := provider.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	s.value = value
+
+	return s, nil
+}
+
+// Name return secret name.
+func (s *Secret) Name() string {
+	return s.name
+}
+
+// Value return current secret value.
+func (s *Secret) Value() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.value
+}
+
+// String implements fmt.Stringer method, value is not printed.
+func (s *Secret) String() string {
+	return "[REDACTED]"
+}
+
+// OnRotate add secret rotation callback.
+func (s *Secret) OnRotate(fn RotateFunc) {
+	s.mu.Lock()
+	s.subs = append(s.subs, fn)
+	s.mu.Unlock()
+}
+
+// Refresh fetch secret value and notify subscribers if value is changed.
+func (s *Secret) Refresh(ctx context.Context) error {
+
+	value, err := s.provider.Get(ctx, s.name)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if value == s.value {
+		s.mu.Unlock()
+		return nil
+	}
+	s.value = value
+	var subs = append([]RotateFunc(nil), s.subs...)
+	s.mu.Unlock()
+
+	logger.FromContext(ctx).WithField("secret", s.name).Info("secret rotated")
+	for _, fn := range subs {
+		fn(value)
+	}
+
+	return nil
+}
+
+// Run refresh secret periodically until context is done.
+func (s *Secret) Run(ctx context.Context) error {
+
+	var ticker = time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil {
+				logger.FromContext(ctx).WithField("secret", s.name).WithErr(err).Error("secret refresh error")
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Package secrets contains secret providers and refreshable secret values.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Provider is secret values source.
+type Provider interface {
+	// Get return secret value by name.
+	Get(ctx context.Context, name string) (string, error)
+}
+
+// Aux error types.
+var (
+	ErrNotFound = errors.New("secret not found") // No secret with such name.
+)
+
+// EnvProvider read secrets from env variables.
+type EnvProvider struct {
+	prefix string
+}
+
+// NewEnvProvider create new env provider, name is prefixed with prefix.
+func NewEnvProvider(prefix string) *EnvProvider {
+	return &EnvProvider{prefix: prefix}
+}
+
+// Get implements Provider Get method.
+func (ep *EnvProvider) Get(_ context.Context, name string) (string, error) {
+	if v, ok := os.LookupEnv(ep.prefix + name); ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("%w: env %s", ErrNotFound, ep.prefix+name)
+}
+
+// FileProvider read secrets from files in directory (kubernetes mounted
+// secrets, docker secrets, etc). Trailing new line is trimmed.
+type FileProvider struct {
+	dir string
+}
+
+// NewFileProvider create new file provider for directory.
+func NewFileProvider(dir string) *FileProvider {
+	return &FileProvider{dir: dir}
+}
+
+// Get implements Provider Get method.
+func (fp *FileProvider) Get(_ context.Context, name string) (string, error) {
+
+	if strings.Contains(name, "..") {
+		return "", fmt.Errorf("invalid secret name %q", name)
+	}
+
+	data, err := os.ReadFile(filepath.Join(fp.dir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%w: file %s", ErrNotFound, name)
+		}
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
@@ -0,0 +1,42 @@
+package secrets
+
+import (
+	"net/http"
+	"time"
+)
+
+type (
+	// secretOption is secret constructor optional modificator.
+	secretOption func(*Secret)
+
+	// vaultOption is vault provider constructor optional modificator.
+	vaultOption func(*VaultProvider)
+)
+
+// WithRefreshInterval setup secret refresh interval.
+func WithRefreshInterval(interval time.Duration) secretOption {
+	return func(s *Secret) {
+		s.interval = interval
+	}
+}
+
+// WithVaultMount setup KV v2 secrets engine mount path ("secret" by default).
+func WithVaultMount(mount string) vaultOption {
+	return func(vp *VaultProvider) {
+		vp.mount = mount
+	}
+}
+
+// WithVaultNamespace setup vault namespace.
+func WithVaultNamespace(namespace string) vaultOption {
+	return func(vp *VaultProvider) {
+		vp.namespace = namespace
+	}
+}
+
+// WithVaultHTTPClient setup custom http client (10 seconds timeout by default).
+func WithVaultHTTPClient(client *http.Client) vaultOption {
+	return func(vp *VaultProvider) {
+		vp.client = client
+	}
+}
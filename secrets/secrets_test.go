@@ -0,0 +1,218 @@
+package secrets_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/secrets"
+)
+
+func TestEnvProvider(t *testing.T) {
+
+	t.Setenv("TEST_SENTRY_DSN", "dsn")
+
+	var p = secrets.NewEnvProvider("TEST_")
+	v, err := p.Get(context.Background(), "SENTRY_DSN")
+	require.ErrorIsf(t, err, nil, "TestEnvProvider: unexpected get error: %v", err)
+	require.Equalf(t, "dsn", v, "TestEnvProvider: unexpected value")
+
+	_, err = p.Get(context.Background(), "UNKNOWN")
+	require.ErrorIsf(t, err, secrets.ErrNotFound, "TestEnvProvider: unexpected get error: %v", err)
+}
+
+func TestFileProviderRotation(t *testing.T) {
+
+	var (
+		ctx  = context.Background()
+		dir  = t.TempDir()
+		path = filepath.Join(dir, "redis-password")
+	)
+
+	err := os.WriteFile(path, []byte("first\n"), 0600)
+	require.ErrorIsf(t, err, nil, "TestFileProviderRotation: unexpected write error: %v", err)
+
+	s, err := secrets.NewSecret(ctx, secrets.NewFileProvider(dir), "redis-password")
+	require.ErrorIsf(t, err, nil, "TestFileProviderRotation: unexpected secret error: %v", err)
+	require.Equalf(t, "first", s.Value(), "TestFileProviderRotation: unexpected value")
+
+	var rotated string
+	s.OnRotate(func(value string) {
+		rotated = value
+	})
+
+	err = os.WriteFile(path, []byte("second\n"), 0600)
+	require.ErrorIsf(t, err, nil, "TestFileProviderRotation: unexpected write error: %v", err)
+
+	err = s.Refresh(ctx)
+	require.ErrorIsf(t, err, nil, "TestFileProviderRotation: unexpected refresh error: %v", err)
+	require.Equalf(t, "second", s.Value(), "TestFileProviderRotation: value not refreshed")
+	require.Equalf(t, "second", rotated, "TestFileProviderRotation: rotation not notified")
+}
+
+func TestSecretInvalidInterval(t *testing.T) {
+
+	_, err := secrets.NewSecret(context.Background(), secrets.NewEnvProvider("TEST_"), "REDIS_PASSWORD",
+		secrets.WithRefreshInterval(0),
+	)
+	require.Errorf(t, err, "TestSecretInvalidInterval: invalid refresh interval is accepted")
+}
+
+func TestVaultProvider(t *testing.T) {
+
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/secret/data/service/nats" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"data": map[string]interface{}{"token": "nats-token"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	var (
+		ctx = context.Background()
+		p   = secrets.NewVaultProvider(server.URL, "root")
+	)
+
+	v, err := p.Get(ctx, "service/nats#token")
+	require.ErrorIsf(t, err, nil, "TestVaultProvider: unexpected get error: %v", err)
+	require.Equalf(t, "nats-token", v, "TestVaultProvider: unexpected value")
+
+	_, err = p.Get(ctx, "service/nats#password")
+	require.ErrorIsf(t, err, secrets.ErrNotFound, "TestVaultProvider: unexpected get error: %v", err)
+
+	_, err = p.Get(ctx, "service/unknown#token")
+	require.ErrorIsf(t, err, secrets.ErrNotFound, "TestVaultProvider: unexpected get error: %v", err)
+
+	_, err = secrets.NewVaultProvider(server.URL, "invalid").Get(ctx, "service/nats#token")
+	require.Errorf(t, err, "TestVaultProvider: invalid token accepted")
+}
+
+// fakeRedis accept connections, record AUTH commands and reply OK to all.
+type fakeRedis struct {
+	net.Listener
+	mu   sync.Mutex
+	auth [][]string
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.ErrorIsf(t, err, nil, "%s: unexpected listen error: %v", t.Name(), err)
+
+	var fr = &fakeRedis{Listener: ln}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go fr.serve(conn)
+		}
+	}()
+
+	return fr
+}
+
+func (fr *fakeRedis) serve(conn net.Conn) {
+
+	defer conn.Close()
+
+	var r = bufio.NewReader(conn)
+	for {
+		var n int
+		if _, err := fmt.Fscanf(r, "*%d\r\n", &n); err != nil {
+			return
+		}
+
+		var args = make([]string, n)
+		for i := range args {
+			var size int
+			if _, err := fmt.Fscanf(r, "$%d\r\n", &size); err != nil {
+				return
+			}
+			var buf = make([]byte, size+2)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return
+			}
+			args[i] = string(buf[:size])
+		}
+
+		if strings.EqualFold(args[0], "auth") {
+			fr.mu.Lock()
+			fr.auth = append(fr.auth, args[1:])
+			fr.mu.Unlock()
+		}
+
+		if _, err := conn.Write([]byte("+OK\r\n")); err != nil {
+			return
+		}
+	}
+}
+
+func (fr *fakeRedis) authCalls() [][]string {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	return append([][]string(nil), fr.auth...)
+}
+
+func TestRedisOnConnect(t *testing.T) {
+
+	var (
+		ctx    = context.Background()
+		server = newFakeRedis(t)
+	)
+	defer server.Close()
+
+	t.Setenv("TEST_REDIS_PASSWORD", "first")
+
+	s, err := secrets.NewSecret(ctx, secrets.NewEnvProvider("TEST_"), "REDIS_PASSWORD")
+	require.ErrorIsf(t, err, nil, "TestRedisOnConnect: unexpected secret error: %v", err)
+
+	for _, username := range []string{"", "user"} {
+		var client = redis.NewClient(&redis.Options{
+			Addr:      server.Addr().String(),
+			OnConnect: secrets.RedisOnConnect(username, s),
+		})
+
+		err = client.Ping(ctx).Err()
+		require.ErrorIsf(t, err, nil, "TestRedisOnConnect: unexpected ping error: %v", err)
+		client.Close()
+	}
+
+	t.Setenv("TEST_REDIS_PASSWORD", "second")
+	err = s.Refresh(ctx)
+	require.ErrorIsf(t, err, nil, "TestRedisOnConnect: unexpected refresh error: %v", err)
+
+	var client = redis.NewClient(&redis.Options{
+		Addr:      server.Addr().String(),
+		OnConnect: secrets.RedisOnConnect("", s),
+	})
+	defer client.Close()
+
+	err = client.Ping(ctx).Err()
+	require.ErrorIsf(t, err, nil, "TestRedisOnConnect: unexpected ping error: %v", err)
+
+	require.Equalf(t, [][]string{{"first"}, {"user", "first"}, {"second"}}, server.authCalls(),
+		"TestRedisOnConnect: unexpected auth commands")
+}
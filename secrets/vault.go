@@ -0,0 +1,96 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// VaultProvider read secrets from Vault compatible KV v2 HTTP API. Secret
+// name is "path#field", for example "service/redis#password".
+type VaultProvider struct {
+	addr      string
+	token     string
+	mount     string
+	namespace string
+	client    *http.Client
+}
+
+// vaultResponse is KV v2 read response.
+type vaultResponse struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+}
+
+// Defaults.
+const (
+	defaultVaultMount   = "secret"
+	defaultVaultTimeout = 10 * time.Second
+)
+
+// NewVaultProvider create new vault provider.
+func NewVaultProvider(addr, token string, opts ...vaultOption) *VaultProvider {
+
+	var vp = &VaultProvider{
+		addr:   strings.TrimRight(addr, "/"),
+		token:  token,
+		mount:  defaultVaultMount,
+		client: &http.Client{Timeout: defaultVaultTimeout},
+	}
+
+	for _, opt := range opts {
+		opt(vp)
+	}
+
+	return vp
+}
+
+// Get implements Provider Get method.
+func (vp *VaultProvider) Get(ctx context.Context, name string) (string, error) {
+
+	var path, field = name, "value"
+	if i := strings.LastIndex(name, "#"); i >= 0 {
+		path, field = name[:i], name[i+1:]
+	}
+
+	var url = fmt.Sprintf("%s/v1/%s/data/%s", vp.addr, vp.mount, strings.TrimLeft(path, "/"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create vault request: %w", err)
+	}
+
+	req.Header.Set("X-Vault-Token", vp.token)
+	if vp.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", vp.namespace)
+	}
+
+	resp, err := vp.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send vault request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", fmt.Errorf("%w: vault path %s", ErrNotFound, path)
+	default:
+		return "", fmt.Errorf("unexpected vault response status: %d", resp.StatusCode)
+	}
+
+	var vr vaultResponse
+	if err := json.NewDecoder(resp.Body).Decode(&vr); err != nil {
+		return "", fmt.Errorf("failed to decode vault response: %w", err)
+	}
+
+	v, ok := vr.Data.Data[field]
+	if !ok {
+		return "", fmt.Errorf("%w: vault field %s#%s", ErrNotFound, path, field)
+	}
+
+	return fmt.Sprint(v), nil
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 
 	"github.com/opentracing/opentracing-go"
+	"github.com/tarusov/rig/buildinfo"
 	"github.com/uber/jaeger-client-go"
 	"github.com/uber/jaeger-client-go/config"
 )
@@ -36,6 +37,12 @@ const (
 	defaultSamplerParam = 0.0
 )
 
+// Build information tags.
+const (
+	tagVersion = "version"
+	tagCommit  = "commit"
+)
+
 // New creates new jaeger tracer instance.
 func New(opts ...tracerOption) (*Tracer, error) {
 
@@ -59,9 +66,15 @@ func New(opts ...tracerOption) (*Tracer, error) {
 		},
 	}
 
-	if len(t.tags) != 0 {
+	// Build information tags are added unless overridden by WithTags.
+	var tagsMap = buildInfoTags()
+	for k, v := range t.tags {
+		tagsMap[k] = v
+	}
+
+	if len(tagsMap) != 0 {
 		var tags = make([]opentracing.Tag, 0)
-		for k, v := range t.tags {
+		for k, v := range tagsMap {
 			tags = append(tags, opentracing.Tag{Key: k, Value: v})
 		}
 		jc.Tags = tags
@@ -83,3 +96,21 @@ func New(opts ...tracerOption) (*Tracer, error) {
 		Closer: tc,
 	}, nil
 }
+
+// buildInfoTags return build information tags, if set via ldflags.
+func buildInfoTags() opentracing.Tags {
+
+	var (
+		info = buildinfo.Get()
+		tags = make(opentracing.Tags)
+	)
+
+	if info.Version != "" {
+		tags[tagVersion] = info.Version
+	}
+	if info.Commit != "" {
+		tags[tagCommit] = info.Commit
+	}
+
+	return tags
+}
@@ -0,0 +1,292 @@
+// Package workerpool contains in-process background tasks queue.
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/tarusov/rig/logger"
+	"github.com/tarusov/rig/metrics"
+)
+
+type (
+	// Pool struct.
+	Pool struct {
+		mu      sync.RWMutex
+		queue   chan task
+		done    chan struct{}  // Closed on shutdown, it releases blocked submitters.
+		sending sync.WaitGroup // Submitters, which may send to queue.
+		started bool
+		closed  bool
+
+		name         string
+		workers      int
+		policy       Policy
+		drainTimeout time.Duration
+		notifier     *logger.SentryNotifier
+
+		depth    metrics.Gauge
+		duration metrics.Duration
+		tasks    metrics.Count
+	}
+
+	// TaskFunc is background task func.
+	TaskFunc func(ctx context.Context) error
+
+	// Policy defines Submit behaviour when queue is full.
+	Policy int
+
+	// task is queued task.
+	task struct {
+		ctx context.Context
+		fn  TaskFunc
+	}
+)
+
+// Policy type enum.
+const (
+	PolicyReject Policy = iota // Submit returns ErrQueueFull.
+	PolicyBlock                // Submit waits for free queue slot, context done or pool shutdown.
+)
+
+// Task results.
+const (
+	resultSuccess  = "success"
+	resultError    = "error"
+	resultPanic    = "panic"
+	resultRejected = "rejected"
+)
+
+// Defaults.
+const (
+	defaultName         = "default"
+	defaultWorkers      = 4
+	defaultQueueSize    = 100
+	defaultPolicy       = PolicyReject
+	defaultDrainTimeout = 30 * time.Second
+)
+
+// Aux error types.
+var (
+	ErrQueueFull      = errors.New("task queue is full")          // Task rejected by full queue.
+	ErrPoolClosed     = errors.New("worker pool is closed")       // Pool does not accept tasks.
+	ErrAlreadyStarted = errors.New("worker pool already started") // Pool can't be run twice.
+	ErrDrainTimeout   = errors.New("worker pool drain timeout")   // Queued tasks are not completed in time.
+)
+
+// New creates new worker pool instance.
+func New(opts ...poolOption) (*Pool, error) {
+
+	var po = &poolOptions{
+		name:         defaultName,
+		workers:      defaultWorkers,
+		queueSize:    defaultQueueSize,
+		policy:       defaultPolicy,
+		drainTimeout: defaultDrainTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(po)
+	}
+
+	if po.workers <= 0 {
+		return nil, fmt.Errorf("invalid workers count: %d", po.workers)
+	}
+	if po.queueSize < 0 {
+		return nil, fmt.Errorf("invalid queue size: %d", po.queueSize)
+	}
+
+	var p = &Pool{
+		queue:        make(chan task, po.queueSize),
+		done:         make(chan struct{}),
+		name:         po.name,
+		workers:      po.workers,
+		policy:       po.policy,
+		drainTimeout: po.drainTimeout,
+		notifier:     po.notifier,
+	}
+
+	if po.registry != nil {
+		var err error
+
+		p.depth, err = metrics.NewGauge(po.registry,
+			"workerpool_queue_depth",
+			"Number of queued tasks.",
+			"pool",
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		p.duration, err = metrics.NewDuration(po.registry,
+			"workerpool_task_duration_seconds",
+			"Duration of tasks in seconds.",
+			"pool", "result",
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		p.tasks, err = metrics.NewCount(po.registry,
+			"workerpool_tasks_total",
+			"Total number of submitted tasks.",
+			"pool", "result",
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// Submit put task into queue. Task will be called with given context.
+func (p *Pool) Submit(ctx context.Context, fn TaskFunc) error {
+
+	// Lock is not held while submitter is blocked, so shutdown is not delayed.
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return ErrPoolClosed
+	}
+	p.sending.Add(1)
+	p.mu.RUnlock()
+
+	defer p.sending.Done()
+
+	var t = task{ctx: ctx, fn: fn}
+
+	switch p.policy {
+	case PolicyBlock:
+		select {
+		case p.queue <- t:
+		case <-p.done:
+			p.count(resultRejected)
+			return ErrPoolClosed
+		case <-ctx.Done():
+			p.count(resultRejected)
+			return ctx.Err()
+		}
+	default:
+		select {
+		case p.queue <- t:
+		default:
+			p.count(resultRejected)
+			return ErrQueueFull
+		}
+	}
+
+	p.updateDepth()
+	return nil
+}
+
+// Run starts workers and blocks until context is done. After that pool stops
+// accepting new tasks and waits for queued tasks completion (drain timeout).
+func (p *Pool) Run(ctx context.Context) error {
+
+	p.mu.Lock()
+	if p.started || p.closed {
+		p.mu.Unlock()
+		return ErrAlreadyStarted
+	}
+	p.started = true
+	p.mu.Unlock()
+
+	var (
+		log = logger.FromContext(ctx).WithField("pool", p.name)
+		wg  sync.WaitGroup
+	)
+
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range p.queue {
+				p.updateDepth()
+				p.execute(t)
+			}
+		}()
+	}
+
+	log.WithField("workers", p.workers).Info("worker pool started")
+	<-ctx.Done()
+
+	p.mu.Lock()
+	p.closed = true
+	close(p.done)
+	p.mu.Unlock()
+
+	// Queue is closed after all submitters are done, blocked ones are
+	// released by done channel.
+	p.sending.Wait()
+	close(p.queue)
+
+	log.WithField("queued", len(p.queue)).Info("worker pool draining")
+
+	var drained = make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		log.Info("worker pool stopped")
+		return nil
+	case <-time.After(p.drainTimeout):
+		log.WithField("queued", len(p.queue)).Error("worker pool drain timeout")
+		return ErrDrainTimeout
+	}
+}
+
+// execute run task with panic recovery.
+func (p *Pool) execute(t task) {
+
+	var (
+		start  = time.Now()
+		result = resultSuccess
+	)
+
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			logger.FromContext(t.ctx).WithFields(logger.Fields{
+				"pool":  p.name,
+				"panic": rvr,
+				"stack": string(debug.Stack()),
+			}).Error("task panic recovered")
+
+			if p.notifier != nil {
+				p.notifier.Recover(rvr)
+			}
+			result = resultPanic
+		}
+
+		p.count(result)
+		if p.duration != nil {
+			p.duration.WithLabelValues(p.name, result).Observe(time.Since(start).Seconds())
+		}
+	}()
+
+	if err := t.fn(t.ctx); err != nil {
+		logger.FromContext(t.ctx).WithField("pool", p.name).WithErr(err).Error("task failed")
+		result = resultError
+	}
+}
+
+// count task result.
+func (p *Pool) count(result string) {
+	if p.tasks != nil {
+		p.tasks.WithLabelValues(p.name, result).Inc()
+	}
+}
+
+// updateDepth set queue depth gauge.
+func (p *Pool) updateDepth() {
+	if p.depth != nil {
+		p.depth.WithLabelValues(p.name).Set(float64(len(p.queue)))
+	}
+}
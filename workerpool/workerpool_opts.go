@@ -0,0 +1,73 @@
+package workerpool
+
+import (
+	"time"
+
+	"github.com/tarusov/rig/logger"
+	"github.com/tarusov/rig/metrics"
+)
+
+type (
+	// poolOption is pool constructor optional modificator.
+	poolOption func(*poolOptions)
+
+	// poolOptions is auxilary constructor struct.
+	poolOptions struct {
+		name         string
+		workers      int
+		queueSize    int
+		policy       Policy
+		drainTimeout time.Duration
+		notifier     *logger.SentryNotifier
+		registry     metrics.Registry
+	}
+)
+
+// WithName setup pool name, it is used as metrics label and logger field.
+func WithName(name string) poolOption {
+	return func(po *poolOptions) {
+		po.name = name
+	}
+}
+
+// WithWorkers setup count of concurrent workers.
+func WithWorkers(n int) poolOption {
+	return func(po *poolOptions) {
+		po.workers = n
+	}
+}
+
+// WithQueueSize setup max count of queued tasks.
+func WithQueueSize(n int) poolOption {
+	return func(po *poolOptions) {
+		po.queueSize = n
+	}
+}
+
+// WithPolicy setup Submit behaviour for full queue.
+func WithPolicy(policy Policy) poolOption {
+	return func(po *poolOptions) {
+		po.policy = policy
+	}
+}
+
+// WithDrainTimeout setup max duration of queued tasks completion on shutdown.
+func WithDrainTimeout(timeout time.Duration) poolOption {
+	return func(po *poolOptions) {
+		po.drainTimeout = timeout
+	}
+}
+
+// WithSentryNotifier setup sentry notifier for tasks panics.
+func WithSentryNotifier(sn *logger.SentryNotifier) poolOption {
+	return func(po *poolOptions) {
+		po.notifier = sn
+	}
+}
+
+// WithMetrics setup registry for queue depth and task duration metrics.
+func WithMetrics(registry metrics.Registry) poolOption {
+	return func(po *poolOptions) {
+		po.registry = registry
+	}
+}
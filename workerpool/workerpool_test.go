@@ -0,0 +1,156 @@
+package workerpool_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tarusov/rig/metrics"
+	"github.com/tarusov/rig/workerpool"
+)
+
+func TestPoolDrain(t *testing.T) {
+
+	p, err := workerpool.New(
+		workerpool.WithWorkers(2),
+		workerpool.WithQueueSize(10),
+		workerpool.WithMetrics(metrics.New()),
+	)
+	require.ErrorIsf(t, err, nil, "TestPoolDrain: unexpected pool error: %v", err)
+
+	var done int32
+	for i := 0; i < 10; i++ {
+		err = p.Submit(context.Background(), func(context.Context) error {
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&done, 1)
+			return nil
+		})
+		require.ErrorIsf(t, err, nil, "TestPoolDrain: unexpected submit error: %v", err)
+	}
+
+	// Context is already cancelled, so pool must drain queued tasks and stop.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = p.Run(ctx)
+	require.ErrorIsf(t, err, nil, "TestPoolDrain: unexpected run error: %v", err)
+	require.Equalf(t, int32(10), atomic.LoadInt32(&done), "TestPoolDrain: queued tasks not drained")
+
+	err = p.Submit(context.Background(), func(context.Context) error { return nil })
+	require.ErrorIsf(t, err, workerpool.ErrPoolClosed, "TestPoolDrain: unexpected submit error: %v", err)
+}
+
+func TestPoolReject(t *testing.T) {
+
+	p, err := workerpool.New(workerpool.WithQueueSize(1))
+	require.ErrorIsf(t, err, nil, "TestPoolReject: unexpected pool error: %v", err)
+
+	var noop = func(context.Context) error { return nil }
+
+	err = p.Submit(context.Background(), noop)
+	require.ErrorIsf(t, err, nil, "TestPoolReject: unexpected submit error: %v", err)
+
+	err = p.Submit(context.Background(), noop)
+	require.ErrorIsf(t, err, workerpool.ErrQueueFull, "TestPoolReject: unexpected submit error: %v", err)
+}
+
+func TestPoolBlock(t *testing.T) {
+
+	p, err := workerpool.New(
+		workerpool.WithQueueSize(1),
+		workerpool.WithPolicy(workerpool.PolicyBlock),
+	)
+	require.ErrorIsf(t, err, nil, "TestPoolBlock: unexpected pool error: %v", err)
+
+	var noop = func(context.Context) error { return nil }
+
+	err = p.Submit(context.Background(), noop)
+	require.ErrorIsf(t, err, nil, "TestPoolBlock: unexpected submit error: %v", err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err = p.Submit(ctx, noop)
+	require.ErrorIsf(t, err, context.DeadlineExceeded, "TestPoolBlock: unexpected submit error: %v", err)
+}
+
+func TestPoolBlockShutdown(t *testing.T) {
+
+	p, err := workerpool.New(
+		workerpool.WithWorkers(1),
+		workerpool.WithQueueSize(1),
+		workerpool.WithPolicy(workerpool.PolicyBlock),
+	)
+	require.ErrorIsf(t, err, nil, "TestPoolBlockShutdown: unexpected pool error: %v", err)
+
+	var (
+		release = make(chan struct{})
+		started = make(chan struct{})
+		blocked = make(chan error, 1)
+		stopped = make(chan error, 1)
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() { stopped <- p.Run(ctx) }()
+
+	// Worker is busy and queue is full, so next submitter is blocked.
+	err = p.Submit(context.Background(), func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	require.ErrorIsf(t, err, nil, "TestPoolBlockShutdown: unexpected submit error: %v", err)
+	<-started
+
+	var noop = func(context.Context) error { return nil }
+
+	err = p.Submit(context.Background(), noop)
+	require.ErrorIsf(t, err, nil, "TestPoolBlockShutdown: unexpected submit error: %v", err)
+
+	go func() { blocked <- p.Submit(context.Background(), noop) }()
+	time.Sleep(10 * time.Millisecond)
+
+	cancel()
+
+	select {
+	case err = <-blocked:
+		require.ErrorIsf(t, err, workerpool.ErrPoolClosed, "TestPoolBlockShutdown: unexpected submit error: %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("TestPoolBlockShutdown: blocked submitter is not released on shutdown")
+	}
+
+	err = p.Submit(context.Background(), noop)
+	require.ErrorIsf(t, err, workerpool.ErrPoolClosed, "TestPoolBlockShutdown: unexpected submit error: %v", err)
+
+	close(release)
+
+	err = <-stopped
+	require.ErrorIsf(t, err, nil, "TestPoolBlockShutdown: unexpected run error: %v", err)
+}
+
+func TestPoolPanic(t *testing.T) {
+
+	p, err := workerpool.New(workerpool.WithWorkers(1))
+	require.ErrorIsf(t, err, nil, "TestPoolPanic: unexpected pool error: %v", err)
+
+	var done int32
+	err = p.Submit(context.Background(), func(context.Context) error { panic("boom") })
+	require.ErrorIsf(t, err, nil, "TestPoolPanic: unexpected submit error: %v", err)
+
+	err = p.Submit(context.Background(), func(context.Context) error {
+		atomic.AddInt32(&done, 1)
+		return nil
+	})
+	require.ErrorIsf(t, err, nil, "TestPoolPanic: unexpected submit error: %v", err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = p.Run(ctx)
+	require.ErrorIsf(t, err, nil, "TestPoolPanic: unexpected run error: %v", err)
+	require.Equalf(t, int32(1), atomic.LoadInt32(&done), "TestPoolPanic: worker not recovered after panic")
+}